@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"lampa/internal/cache"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	OptOlderThan = "older-than"
+)
+
+func CreateCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "manage cached reports (" + cache.EnvCacheDir + " to change location)",
+		Commands: []*cli.Command{
+			{
+				Name:   "ls",
+				Usage:  "list cached reports",
+				Action: CmdActionList,
+			},
+			{
+				Name:  "prune",
+				Usage: "remove cached reports",
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  OptOlderThan,
+						Usage: "remove only reports older than duration (e.g. 720h)",
+					},
+				},
+				Action: CmdActionPrune,
+			},
+		},
+	}
+}
+
+func CmdActionList(ctx context.Context, cmd *cli.Command) error {
+	c := cache.New(cache.DefaultDir())
+	entries, err := c.List()
+	if err != nil {
+		return fmt.Errorf("could not read cache `%s`: %v", c.Dir, err)
+	}
+
+	fmt.Printf("Cache directory: %s\n\n", c.Dir)
+	if len(entries) == 0 {
+		fmt.Println("No cached reports.")
+		return nil
+	}
+
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+		fmt.Printf("%s  %s  %-10s %-30s %s  %s\n",
+			e.Hash[:12],
+			shortCommit(e.Key.Commit),
+			e.Key.Variant,
+			strings.Join(e.Key.Modules, ","),
+			e.CreatedAt.Local().Format(time.DateTime),
+			formatSize(e.Size),
+		)
+	}
+	fmt.Printf("\n%d report(s), %s\n", len(entries), formatSize(total))
+	return nil
+}
+
+func CmdActionPrune(ctx context.Context, cmd *cli.Command) error {
+	c := cache.New(cache.DefaultDir())
+	removed, err := c.Prune(cmd.Duration(OptOlderThan))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed %d cached report(s) from %s\n", removed, c.Dir)
+	return nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 10 {
+		return commit[:10]
+	}
+	return commit
+}
+
+func formatSize(size int64) string {
+	if size < 1024*1024 {
+		return fmt.Sprintf("%.1f KiB", float64(size)/1024)
+	}
+	return fmt.Sprintf("%.1f MiB", float64(size)/1024/1024)
+}
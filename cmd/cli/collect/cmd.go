@@ -6,10 +6,20 @@ import (
 	"crypto/sha1"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"lampa/internal"
+	"lampa/internal/cache"
+	"lampa/internal/catalog"
+	"lampa/internal/config"
+	"lampa/internal/findings"
+	"lampa/internal/gradle"
+	"lampa/internal/gradlecache"
 	"lampa/internal/out"
+	"lampa/internal/policy"
+	"lampa/internal/proc"
+	"lampa/internal/progress"
 	"lampa/internal/report"
 	pages "lampa/internal/templates/html"
 	"lampa/internal/utils"
@@ -27,8 +37,6 @@ import (
 	"github.com/fatih/color"
 	"github.com/samber/lo"
 	"github.com/urfave/cli/v3"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 
 	. "lampa/internal/globals"
 )
@@ -39,89 +47,267 @@ const (
 )
 
 const (
-	OptProjectDir   = "project"
-	OptReportsDir   = "to-dir"
-	OptBuildVariant = "variant"
-	OptFormat       = "format"
-
+	OptProjectDir      = "project"
+	OptConfigFile      = "config"
+	OptReportsDir      = "to-dir"
+	OptBuildVariant    = "variant"
+	OptModules         = "module"
+	OptConfigurations  = "configuration"
+	OptFormat          = "format"
 	OptOverwriteReport = "overwrite"
 	OptFileName        = "file-name"
+	OptGradleDaemon    = "gradle-daemon"
+	OptVerbose         = "verbose"
+	OptLogFormat       = "log-format"
+	OptBuildTimeout    = "build-timeout"
+	OptToolTimeout     = "tool-timeout"
+	OptNoCache         = "no-cache"
+	OptCombineVariants = "combine-variants"
 )
 
+const (
+	DefaultBuildVariant = "release"
+	DefaultModule       = "app"
+	DefaultFileName     = "report.lampa"
+	DefaultFormat       = "json"
+	DefaultBuildTimeout = time.Hour
+	DefaultToolTimeout  = 5 * time.Minute
+)
+
+// Replaced with build variant in configuration names
+const VariantPlaceholder = "{variant}"
+
 func CreateCliCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "collect",
 		Usage: "generate project report",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:  OptProjectDir,
-				Usage: "project directory root",
-				Value: ".",
+				Name:    OptProjectDir,
+				Usage:   "project directory root",
+				Value:   ".",
+				Sources: cli.EnvVars("LAMPA_PROJECT"),
 			},
 			&cli.StringFlag{
-				Name:  OptReportsDir,
-				Usage: "directory where to put report",
-				Value: ".",
+				Name:    OptConfigFile,
+				Usage:   "config file (by default lampa.toml/.lampa.yaml is looked up in project directory)",
+				Sources: cli.EnvVars("LAMPA_CONFIG"),
 			},
 			&cli.StringFlag{
-				Name:  OptBuildVariant,
-				Usage: "build variant to use",
-				Value: "release",
+				Name:    OptReportsDir,
+				Usage:   "directory where to put report",
+				Value:   ".",
+				Sources: cli.EnvVars("LAMPA_TO_DIR"),
+			},
+			&cli.StringSliceFlag{
+				Name:    OptBuildVariant,
+				Usage:   "build variants to collect, globs are supported (e.g. '*Release')",
+				Value:   []string{DefaultBuildVariant},
+				Sources: cli.EnvVars("LAMPA_VARIANT"),
+			},
+			&cli.BoolFlag{
+				Name:    OptCombineVariants,
+				Usage:   "produce a single report for all collected variants",
+				Sources: cli.EnvVars("LAMPA_COMBINE_VARIANTS"),
+			},
+			&cli.StringSliceFlag{
+				Name:    OptModules,
+				Usage:   "Gradle modules to collect dependencies from (first one is the application module)",
+				Value:   []string{DefaultModule},
+				Sources: cli.EnvVars("LAMPA_MODULES"),
+			},
+			&cli.StringSliceFlag{
+				Name:    OptConfigurations,
+				Usage:   "Gradle configurations to collect dependencies from, " + VariantPlaceholder + " is replaced with build variant (default: " + VariantPlaceholder + "CompileClasspath)",
+				Sources: cli.EnvVars("LAMPA_CONFIGURATIONS"),
 			},
 			&cli.StringFlag{
-				Name:  OptFileName,
-				Usage: "report file name (without extension)",
-				Value: "report.lampa",
+				Name:    OptFileName,
+				Usage:   "report file name (without extension)",
+				Value:   DefaultFileName,
+				Sources: cli.EnvVars("LAMPA_FILE_NAME"),
 			},
 			&cli.StringFlag{
-				Name:  OptFormat,
-				Usage: "report formats to produce delimited with ',' (json,html)",
-				Value: "json",
+				Name:    OptFormat,
+				Usage:   "report formats to produce delimited with ',' (json,html,junit,sarif)",
+				Value:   DefaultFormat,
+				Sources: cli.EnvVars("LAMPA_FORMAT"),
 			},
 
 			&cli.BoolFlag{
 				Name:  OptOverwriteReport,
-				Usage: "allow overwriting report file if it exists",
+				Usage: "allow overwriting report files (and build log) if they exist",
+			},
+			&cli.BoolFlag{
+				Name:    OptGradleDaemon,
+				Usage:   "keep Gradle daemon running to speed up next runs",
+				Sources: cli.EnvVars("LAMPA_GRADLE_DAEMON"),
+			},
+			&cli.BoolFlag{
+				Name:    OptVerbose,
+				Usage:   "print Gradle output while building",
+				Sources: cli.EnvVars("LAMPA_VERBOSE"),
+			},
+			&cli.StringFlag{
+				Name:    OptLogFormat,
+				Usage:   "format of build log and verbose output (text,json)",
+				Value:   progress.FormatText,
+				Sources: cli.EnvVars("LAMPA_LOG_FORMAT"),
+			},
+			&cli.DurationFlag{
+				Name:    OptBuildTimeout,
+				Usage:   "maximum duration of Gradle build (0 to disable)",
+				Value:   DefaultBuildTimeout,
+				Sources: cli.EnvVars("LAMPA_BUILD_TIMEOUT"),
+			},
+			&cli.BoolFlag{
+				Name:    OptNoCache,
+				Usage:   "don't use cached report for the current commit",
+				Sources: cli.EnvVars("LAMPA_NO_CACHE"),
+			},
+			&cli.DurationFlag{
+				Name:    OptToolTimeout,
+				Usage:   "maximum duration of other tool invocations: bundletool, aapt2, git (0 to disable)",
+				Value:   DefaultToolTimeout,
+				Sources: cli.EnvVars("LAMPA_TOOL_TIMEOUT"),
 			},
 		},
 		Action: CmdActionCollect,
 	}
 }
 
-func parseExecArgs(c *cli.Command) ExecArgs {
+func parseExecArgs(c *cli.Command) (ExecArgs, error) {
 	args := ExecArgs{}
 
 	args.ProjectDir = c.String(OptProjectDir)
 	args.ProjectDir = utils.TryResolveFsPath(args.ProjectDir)
 
+	// Config file
+	var err error
+	if c.IsSet(OptConfigFile) {
+		args.ConfigFile = utils.TryResolveFsPath(c.String(OptConfigFile))
+		args.Config, err = config.Load(args.ConfigFile)
+	} else {
+		args.Config, args.ConfigFile, err = config.LoadFromDir(args.ProjectDir)
+	}
+	if err != nil {
+		return args, err
+	}
+	cfg := args.Config.Collect
+
+	// Flags and environment variables are taking precedence over config values
 	args.ReportsDir = c.String(OptReportsDir)
+	if !c.IsSet(OptReportsDir) && cfg.ToDir != "" {
+		args.ReportsDir = cfg.ToDir
+		if !filepath.IsAbs(args.ReportsDir) && !strings.HasPrefix(args.ReportsDir, "~") {
+			args.ReportsDir = filepath.Join(args.ProjectDir, args.ReportsDir)
+		}
+	}
 
-	args.BuildVariant = c.String(OptBuildVariant)
-	args.BuildVariant = strings.TrimSpace(args.BuildVariant)
+	args.Variants = c.StringSlice(OptBuildVariant)
+	if !c.IsSet(OptBuildVariant) {
+		if len(cfg.Variants) > 0 {
+			args.Variants = cfg.Variants
+		} else if cfg.Variant != "" {
+			args.Variants = []string{cfg.Variant}
+		}
+	}
+	args.Variants = cleanList(args.Variants)
+
+	args.CombineVariants = c.Bool(OptCombineVariants)
+	if !c.IsSet(OptCombineVariants) {
+		args.CombineVariants = cfg.CombineVariants
+	}
+
+	args.Modules = c.StringSlice(OptModules)
+	if !c.IsSet(OptModules) && len(cfg.Modules) > 0 {
+		args.Modules = cfg.Modules
+	}
+	args.Modules = cleanList(args.Modules)
+
+	args.Configurations = c.StringSlice(OptConfigurations)
+	if !c.IsSet(OptConfigurations) && len(cfg.Configurations) > 0 {
+		args.Configurations = cfg.Configurations
+	}
+	args.Configurations = cleanList(args.Configurations)
+	if len(args.Configurations) == 0 {
+		args.Configurations = []string{VariantPlaceholder + "CompileClasspath"}
+	}
 
 	args.OverwriteReport = c.Bool(OptOverwriteReport)
 
+	args.GradleDaemon = c.Bool(OptGradleDaemon)
+	if !c.IsSet(OptGradleDaemon) {
+		args.GradleDaemon = cfg.GradleDaemon
+	}
+
 	formats := strings.Split(c.String(OptFormat), ",")
+	if !c.IsSet(OptFormat) && len(cfg.Formats) > 0 {
+		formats = cfg.Formats
+	}
+	formats = cleanList(formats)
 	args.Formats.Json = lo.Contains(formats, "json")
 	args.Formats.Html = lo.Contains(formats, "html")
+	args.Formats.Junit = lo.Contains(formats, "junit")
+	args.Formats.Sarif = lo.Contains(formats, "sarif")
 
 	reportName := c.String(OptFileName)
-	args.JsonReportFile = path.Join(args.ReportsDir, reportName+".json")
-	args.JsonReportFile = utils.TryResolveFsPath(args.JsonReportFile)
-	args.HtmlReportFile = path.Join(args.ReportsDir, reportName+".html")
-	args.HtmlReportFile = utils.TryResolveFsPath(args.HtmlReportFile)
+	if !c.IsSet(OptFileName) && cfg.FileName != "" {
+		reportName = cfg.FileName
+	}
+	args.setReportName(reportName)
+	args.LogFile = path.Join(args.ReportsDir, reportName+".log")
+	args.LogFile = utils.TryResolveFsPath(args.LogFile)
+
+	args.BuildTimeout = c.Duration(OptBuildTimeout)
+	if !c.IsSet(OptBuildTimeout) && cfg.BuildTimeout != "" {
+		args.BuildTimeout, _ = time.ParseDuration(cfg.BuildTimeout)
+	}
+	args.ToolTimeout = c.Duration(OptToolTimeout)
+	if !c.IsSet(OptToolTimeout) && cfg.ToolTimeout != "" {
+		args.ToolTimeout, _ = time.ParseDuration(cfg.ToolTimeout)
+	}
+
+	args.NoCache = c.Bool(OptNoCache)
+	args.CacheDir = cache.DefaultDir()
+
+	args.Verbose = c.Bool(OptVerbose)
+	args.LogFormat = strings.TrimSpace(c.String(OptLogFormat))
 
 	args.GradlewPath = path.Join(args.ProjectDir, "gradlew")
+	args.GradleHome = gradlecache.DefaultRoot()
 
 	args.AndroidSdkPath = utils.TryResolveFsPath(os.Getenv(EnvAndroidSdkRoot))
 	args.BundletoolPath = utils.TryResolveFsPath(os.Getenv(EnvBundletoolJar))
 
-	return args
+	return args, nil
 }
 
-func validateExecArgs(args *ExecArgs) error {
+// setReportName sets report files in reports directory.
+func (self *ExecArgs) setReportName(name string) {
+	self.ReportName = name
+	self.JsonReportFile = path.Join(self.ReportsDir, name+".json")
+	self.JsonReportFile = utils.TryResolveFsPath(self.JsonReportFile)
+	self.HtmlReportFile = path.Join(self.ReportsDir, name+".html")
+	self.HtmlReportFile = utils.TryResolveFsPath(self.HtmlReportFile)
+	self.JunitReportFile = utils.TryResolveFsPath(path.Join(self.ReportsDir, name+".junit.xml"))
+	self.SarifReportFile = utils.TryResolveFsPath(path.Join(self.ReportsDir, name+".sarif"))
+}
+
+func cleanList(items []string) []string {
+	result := make([]string, 0, len(items))
+	for _, it := range items {
+		it = strings.TrimSpace(it)
+		if it != "" {
+			result = append(result, it)
+		}
+	}
+	return result
+}
+
+func validateExecArgs(ctx context.Context, args *ExecArgs) error {
 	// Build variant
-	if args.BuildVariant == "" {
+	if len(args.Variants) == 0 {
 		return fmt.Errorf("'%s' cannot be empty", OptBuildVariant)
 
 		// TODO Wrapping is not playing well with cli/v3 package
@@ -131,6 +317,16 @@ func validateExecArgs(args *ExecArgs) error {
 		// )
 	}
 
+	// Modules
+	if len(args.Modules) == 0 {
+		return fmt.Errorf("'%s' cannot be empty", OptModules)
+	}
+
+	// Log format
+	if !slices.Contains(progress.Formats, args.LogFormat) {
+		return fmt.Errorf("unknown '%s' value %q (expected one of: %s)", OptLogFormat, args.LogFormat, strings.Join(progress.Formats, ", "))
+	}
+
 	// Project dir
 	info, err := os.Stat(args.ProjectDir)
 	if err != nil {
@@ -144,36 +340,16 @@ func validateExecArgs(args *ExecArgs) error {
 	}
 
 	// Reports
-	if args.Formats.Json {
-		if utils.FileExists(args.JsonReportFile) {
-			if args.OverwriteReport {
-				if utils.IsDir(args.JsonReportFile) {
-					return fmt.Errorf("report file `%s` is a directory", args.JsonReportFile)
-				}
-			} else {
-				return fmt.Errorf("report file `%s` already exists", args.JsonReportFile)
-			}
-		}
-	}
-	if args.Formats.Html {
-		if utils.FileExists(args.HtmlReportFile) {
-			if args.OverwriteReport {
-				if utils.IsDir(args.HtmlReportFile) {
-					return fmt.Errorf("HTML report file `%s` is a directory", args.HtmlReportFile)
-				}
-			} else {
-				return fmt.Errorf("HTML report file `%s` already exists", args.HtmlReportFile)
-			}
-		}
-	}
 	if !args.Formats.Any() {
 		return fmt.Errorf("No report formats selected. Choose at least one.")
 	}
 
 	// Java
-	cmd := exec.Command("java", "--version")
+	javaCtx, cancel := proc.WithTimeout(ctx, args.ToolTimeout)
+	defer cancel()
+	cmd := proc.Command(javaCtx, "java", "--version")
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("java not found or not executable: %v", err)
+		return proc.Wrap(javaCtx, "java check", args.ToolTimeout, fmt.Errorf("java not found or not executable: %v", err))
 	}
 
 	// Bundletool
@@ -218,23 +394,95 @@ func validateExecArgs(args *ExecArgs) error {
 	return nil
 }
 
+// validateReportFiles checks that report files can be written.
+func validateReportFiles(args ExecArgs) error {
+	if args.Formats.Json {
+		if utils.FileExists(args.JsonReportFile) {
+			if args.OverwriteReport {
+				if utils.IsDir(args.JsonReportFile) {
+					return fmt.Errorf("report file `%s` is a directory", args.JsonReportFile)
+				}
+			} else {
+				return fmt.Errorf("report file `%s` already exists", args.JsonReportFile)
+			}
+		}
+	}
+	if args.Formats.Html {
+		if utils.FileExists(args.HtmlReportFile) {
+			if args.OverwriteReport {
+				if utils.IsDir(args.HtmlReportFile) {
+					return fmt.Errorf("HTML report file `%s` is a directory", args.HtmlReportFile)
+				}
+			} else {
+				return fmt.Errorf("HTML report file `%s` already exists", args.HtmlReportFile)
+			}
+		}
+	}
+	for _, it := range []struct {
+		enabled bool
+		file    string
+	}{
+		{args.Formats.Junit, args.JunitReportFile},
+		{args.Formats.Sarif, args.SarifReportFile},
+	} {
+		if it.enabled && utils.FileExists(it.file) && (!args.OverwriteReport || utils.IsDir(it.file)) {
+			return fmt.Errorf("report file `%s` already exists", it.file)
+		}
+	}
+	return nil
+}
+
+// validateLogFile checks that build log can be written (it is written only if Gradle is invoked).
+func validateLogFile(args ExecArgs) error {
+	if utils.FileExists(args.LogFile) && (!args.OverwriteReport || utils.IsDir(args.LogFile)) {
+		return fmt.Errorf("build log `%s` already exists", args.LogFile)
+	}
+	return nil
+}
+
 type FormatArgs struct {
 	Json bool
 	Html bool
+	// Policy check results
+	Junit bool
+	Sarif bool
 }
 
 func (self FormatArgs) Any() bool {
-	return self.Json || self.Html
+	return self.Json || self.Html || self.Junit || self.Sarif
 }
 
 type ExecArgs struct {
 	ProjectDir string
 	ReportsDir string
 
-	JsonReportFile string
-	HtmlReportFile string
+	ConfigFile string
+	Config     config.Config
+
+	ReportName      string
+	JsonReportFile  string
+	HtmlReportFile  string
+	JunitReportFile string
+	SarifReportFile string
+	LogFile         string
+
+	Verbose   bool
+	LogFormat string
+
+	BuildTimeout time.Duration
+	ToolTimeout  time.Duration
+
+	NoCache  bool
+	CacheDir string
 
-	BuildVariant string
+	// Requested variants (names or globs)
+	Variants        []string
+	CombineVariants bool
+
+	// Variant and configurations of a single collection (see `variantRuns`)
+	BuildVariant   string
+	Modules        []string
+	Configurations []string
 
 	OverwriteReport bool
 
@@ -244,41 +492,79 @@ type ExecArgs struct {
 	AndroidSdkPath string
 	AaptPath       string
 	GradlewPath    string
+	GradleHome     string
+	GradleDaemon   bool
 }
 
 func CmdActionCollect(ctx context.Context, cmd *cli.Command) error {
-	args := parseExecArgs(cmd)
-	err := validateExecArgs(&args)
+	args, err := parseExecArgs(cmd)
+	if err != nil {
+		return err
+	}
+	err = validateExecArgs(ctx, &args)
 	if err != nil {
 		return err
 	}
 
-	return execute(args)
+	return execute(ctx, args)
 }
 
-func execute(args ExecArgs) error {
+func execute(ctx context.Context, args ExecArgs) error {
 	// Print run info
 	fmt.Printf("Project directory: %s\n", args.ProjectDir)
+	if args.ConfigFile != "" {
+		fmt.Printf("Config file: %s\n", args.ConfigFile)
+	}
+
+	variants, err := resolveVariants(ctx, args)
+	if err != nil {
+		return err
+	}
+	runs := variantRuns(args, variants)
+	targets := runs
+	if args.CombineVariants && len(runs) > 1 {
+		targets = []ExecArgs{args}
+	}
+	for _, target := range targets {
+		if err := validateReportFiles(target); err != nil {
+			return err
+		}
+	}
+
+	if len(variants) > 1 {
+		fmt.Printf("Build variants: %s\n", strings.Join(variants, ", "))
+	}
 	// fmt.Printf("Report directory: %s\n", to)
-	fmt.Printf("Report file: %s\n", args.JsonReportFile)
-	if args.Formats.Html {
-		fmt.Printf("HTML report file: %s\n", args.HtmlReportFile)
+	for _, target := range targets {
+		fmt.Printf("Report file: %s\n", target.JsonReportFile)
+		if target.Formats.Html {
+			fmt.Printf("HTML report file: %s\n", target.HtmlReportFile)
+		}
+		if target.Formats.Junit {
+			fmt.Printf("JUnit report file: %s\n", target.JunitReportFile)
+		}
+		if target.Formats.Sarif {
+			fmt.Printf("SARIF report file: %s\n", target.SarifReportFile)
+		}
 	}
+	fmt.Printf("Build log: %s\n", args.LogFile)
 	fmt.Println()
 
 	// Print warnings
 	hasWarningSection := false
 	if args.OverwriteReport {
-		if args.Formats.Json {
-			if utils.FileExists(args.JsonReportFile) {
-				hasWarningSection = true
-				out.PrintlnWarn("Existing report file will be overwritten")
+		for _, target := range targets {
+			if target.Formats.Json {
+				if utils.FileExists(target.JsonReportFile) {
+					hasWarningSection = true
+					out.PrintlnWarn("Existing report file `%s` will be overwritten", target.JsonReportFile)
+				}
 			}
-		}
-		if args.Formats.Html {
-			if utils.FileExists(args.HtmlReportFile) {
-				hasWarningSection = true
-				out.PrintlnWarn("Existing HTML report file will be overwritten")
+			if target.Formats.Html {
+				if utils.FileExists(target.HtmlReportFile) {
+					hasWarningSection = true
+					out.PrintlnWarn("Existing HTML report file `%s` will be overwritten", target.HtmlReportFile)
+				}
 			}
 		}
 	}
@@ -286,100 +572,230 @@ func execute(args ExecArgs) error {
 		fmt.Println()
 	}
 
-	_, err := DynamicSpinner(SpinnerArgs{
-		Msg:             "Building...",
-		MsgAfterSuccess: "Building: Done.",
-		MsgAfterFail:    "Building: Failed.",
-	}, func() (string, error) {
-		task := "bundle" + cases.Title(language.BritishEnglish).String(args.BuildVariant)
-		output, err := executeGradleTask(args, task)
+	contextSegment, err := parseContext(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	// Cache
+	reportCache := cache.New(args.CacheDir)
+	useCache := !args.NoCache && contextSegment.Git.Commit != "" && !contextSegment.Git.IsDirty
+	if !args.NoCache && contextSegment.Git.IsDirty {
+		fmt.Printf("Working tree has uncommitted changes: cached reports are not used.\n\n")
+	}
+	reports := make([]*report.Report, len(runs))
+	missing := []ExecArgs{}
+	for i, run := range runs {
+		if useCache {
+			if entry, ok := reportCache.Get(reportCacheKey(run, contextSegment)); ok {
+				fmt.Printf("Using cached %s report for commit %s (collected at %s UTC).\n", run.BuildVariant, contextSegment.Git.Commit, entry.CreatedAt.Format(time.DateTime))
+				reports[i] = &entry.Report
+				continue
+			}
+		}
+		missing = append(missing, run)
+	}
+	if len(missing) < len(runs) {
+		fmt.Printf("Use --%s to collect it again.\n", OptNoCache)
+	}
+
+	if len(missing) > 0 {
+		if err := validateLogFile(args); err != nil {
+			return err
+		}
+		export, err := DynamicSpinnerWithProgress(SpinnerArgs{
+			Msg:             "Building...",
+			MsgAfterSuccess: "Building: Done.",
+			MsgAfterFail:    "Building: Failed.",
+			// Gradle output is printed instead
+			IsPlain: args.Verbose,
+		}, func(progress func(string)) (gradle.Export, error) {
+			return runGradle(ctx, args, missing, progress)
+		})
 		if err != nil {
-			return "", fmt.Errorf("failed to build app: %v\nOutput:\n%s", err, string(output))
+			return err
 		}
 
-		return "", nil
+		for i, run := range runs {
+			if reports[i] != nil {
+				continue
+			}
+
+			r, err := StepReport(ctx, run, contextSegment, *export)
+			if err != nil {
+				return err
+			}
+
+			if useCache {
+				if err := reportCache.Put(reportCacheKey(run, contextSegment), *r); err != nil {
+					out.PrintlnWarn("could not cache report: %v", err)
+				}
+			}
+			reports[i] = r
+		}
+	}
+
+	if len(targets) < len(runs) {
+		combined := report.Combine(reports)
+		return writeReports(targets[0], &combined)
+	}
+	errs := []error{}
+	for i, run := range runs {
+		if err := writeReports(run, reports[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolveVariants expands variant globs with variants of the application module.
+// Gradle is invoked only if there are globs.
+func resolveVariants(ctx context.Context, args ExecArgs) ([]string, error) {
+	if !lo.SomeBy(args.Variants, gradle.IsPattern) {
+		return lo.Uniq(args.Variants), nil
+	}
+
+	available, err := DynamicSpinner(SpinnerArgs{
+		Msg:             "Discovering variants...",
+		MsgAfterSuccess: "Discovering variants: Done.",
+		MsgAfterFail:    "Discovering variants: Failed.",
+	}, func() (gradle.VariantsExport, error) {
+		return listVariants(ctx, args)
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// pathToApk, err := DynamicSpinner(SpinnerArgs{
-	// 	Msg:             "Building...",
-	// 	MsgAfterSuccess: "Building: Done.",
-	// 	MsgAfterFail:    "Building: Failed.",
-	// }, func() (string, error) {
-	// 	task := "assemble" + cases.Title(language.BritishEnglish).String(buildVariant)
-	// 	cmd := exec.Command(gradlewPath, "--no-daemon", "--console", "plain", "-q", task)
-	// 	cmd.Dir = from
-	// 	output, err := cmd.CombinedOutput()
-	// 	if err != nil {
-	// 		return "", fmt.Errorf("failed to build app: %v\nOutput:\n%s", err, string(output))
-	// 	}
+	return gradle.MatchVariants(args.Variants, available.Names())
+}
 
-	// 	var variantPaths []string
-	// 	var wordStart int
-	// 	for i, r := range buildVariant {
-	// 		if i > 0 && unicode.IsUpper(r) {
-	// 			variantPaths = append(variantPaths, strings.ToLower(buildVariant[wordStart:i]))
-	// 			wordStart = i
-	// 		}
-	// 	}
-	// 	variantPaths = append(variantPaths, strings.ToLower(buildVariant[wordStart:]))
+func listVariants(ctx context.Context, args ExecArgs) (gradle.VariantsExport, error) {
+	runner := gradle.Runner{
+		GradlewPath: args.GradlewPath,
+		ProjectDir:  args.ProjectDir,
+		UseDaemon:   args.GradleDaemon,
+	}
+	buildCtx, cancel := proc.WithTimeout(ctx, args.BuildTimeout)
+	defer cancel()
 
-	// 	apkDir := path.Join(append([]string{from, "app", "build", "outputs", "apk"}, variantPaths...)...)
+	result, output, err := runner.ListVariants(buildCtx, args.Modules[0])
+	if buildCtx.Err() != nil {
+		return result, proc.Wrap(buildCtx, "Gradle build", args.BuildTimeout, err)
+	}
+	if err != nil && output != nil {
+		summary := progress.FailureSummary(string(output), maxFailureLines)
+		return result, fmt.Errorf("failed to list build variants: %v\n%s", err, strings.Join(summary, "\n"))
+	}
+	return result, err
+}
 
-	// 	info, err := os.Stat(apkDir)
-	// 	if err != nil {
-	// 		if os.IsNotExist(err) {
-	// 			return "", fmt.Errorf("APK directory `%s` does not exist", apkDir)
-	// 		}
-	// 		return "", fmt.Errorf("error accessing APK directory `%s`: %v", apkDir, err)
-	// 	}
-	// 	if !info.IsDir() {
-	// 		return "", fmt.Errorf("APK directory `%s` is not a directory", apkDir)
-	// 	}
+// variantRuns returns arguments of collection for every variant.
+// Reports of several variants are written to separate files (with variant suffix) unless they are combined.
+func variantRuns(args ExecArgs, variants []string) []ExecArgs {
+	result := []ExecArgs{}
+	for _, variant := range variants {
+		run := args
+		run.BuildVariant = variant
+		run.Configurations = lo.Map(args.Configurations, func(c string, _ int) string {
+			return strings.ReplaceAll(c, VariantPlaceholder, variant)
+		})
+		if len(variants) > 1 && !args.CombineVariants {
+			run.setReportName(args.ReportName + "." + variant)
+		}
+		result = append(result, run)
+	}
+	return result
+}
 
-	// 	files, err := os.ReadDir(apkDir)
-	// 	if err != nil {
-	// 		return "", fmt.Errorf("could not read APK directory `%s`: %v", apkDir, err)
-	// 	}
-	// 	var apkFilePath string
-	// 	for _, file := range files {
-	// 		if !file.IsDir() && strings.HasSuffix(file.Name(), ".apk") {
-	// 			apkFilePath = path.Join(apkDir, file.Name())
-	// 			break
-	// 		}
-	// 	}
-	// 	if apkFilePath == "" {
-	// 		return "", fmt.Errorf("no APK file found in `%s`", apkDir)
-	// 	}
+// reportCacheKey identifies report collected with the same inputs.
+func reportCacheKey(args ExecArgs, contextSegment report.ContextSegment) cache.Key {
+	return cache.Key{
+		Commit:         contextSegment.Git.Commit,
+		Variant:        args.BuildVariant,
+		Modules:        args.Modules,
+		Configurations: args.Configurations,
+		Ignore:         args.Config.Ignore,
+		ToolVersion:    G.Version + "+" + G.BuildCommit,
+	}
+}
 
-	// 	return apkFilePath, nil
-	// })
-	// if err != nil {
-	// 	return err
-	// }
+// runGradle builds the app for all runs and exports their dependencies in a single Gradle invocation.
+// Gradle output is written to the build log.
+func runGradle(ctx context.Context, args ExecArgs, runs []ExecArgs, onTask func(string)) (gradle.Export, error) {
+	tempDir, err := os.MkdirTemp("", "lampa-gradle")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
 
-	err = StepReport(args)
+	initScript, err := gradle.WriteInitScript(tempDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	outputDir := filepath.Join(tempDir, "out")
 
-	return nil
+	var console io.Writer
+	if args.Verbose {
+		console = os.Stdout
+	}
+	buildLog, err := progress.Open(args.LogFile, args.LogFormat, console)
+	if err != nil {
+		return nil, err
+	}
+	defer buildLog.Close()
+
+	runner := gradle.Runner{
+		GradlewPath: args.GradlewPath,
+		ProjectDir:  args.ProjectDir,
+		UseDaemon:   args.GradleDaemon,
+		OnOutput: func(line string) {
+			if task, ok := buildLog.Write(line); ok {
+				onTask(task)
+			}
+		},
+	}
+	buildCtx, cancel := proc.WithTimeout(ctx, args.BuildTimeout)
+	defer cancel()
+
+	tasks := []string{}
+	configurations := []string{}
+	for _, run := range runs {
+		tasks = append(tasks, gradle.BundleTask(run.BuildVariant))
+		configurations = append(configurations, run.Configurations...)
+	}
+	output, err := runner.Run(buildCtx, append(
+		tasks,
+		gradle.TaskArgs(initScript, outputDir, args.Modules, lo.Uniq(configurations))...,
+	)...)
+	if buildCtx.Err() != nil {
+		return nil, fmt.Errorf("%v\n\nFull build log: %s", proc.Wrap(buildCtx, "Gradle build", args.BuildTimeout, err), args.LogFile)
+	}
+	if err != nil {
+		summary := progress.FailureSummary(string(output), maxFailureLines)
+		return nil, fmt.Errorf(
+			"failed to build app: %v\n%s\n\nFull build log: %s",
+			err, strings.Join(summary, "\n"), args.LogFile,
+		)
+	}
+
+	return gradle.ReadExport(outputDir)
 }
 
-func StepReport(args ExecArgs) error {
+// Number of build output lines printed on failure
+const maxFailureLines = 30
+
+func StepReport(ctx context.Context, args ExecArgs, contextSegment report.ContextSegment, export gradle.Export) (*report.Report, error) {
 	pathToAab, err := findAabFile(args)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	report, err := DynamicSpinner(
 		SpinnerArgs{
-			Msg:             "Generating report...",
-			MsgAfterSuccess: "Generating report: Done.",
-			MsgAfterFail:    "Generating report: Failed.",
+			Msg:             fmt.Sprintf("Generating %s report...", args.BuildVariant),
+			MsgAfterSuccess: fmt.Sprintf("Generating %s report: Done.", args.BuildVariant),
+			MsgAfterFail:    fmt.Sprintf("Generating %s report: Failed.", args.BuildVariant),
 		}, func() (report.Report, error) {
-			return collectReport(args, pathToAab)
+			return collectReport(ctx, args, contextSegment, pathToAab, export)
 			// return collectReport(CollectReportArgs{
 			// 	ProjectDir:   args.ProjectDir,
 			// 	ReportDir:    args.ReportsDir,
@@ -393,12 +809,17 @@ func StepReport(args ExecArgs) error {
 			// })
 		})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	return report, nil
+}
+
+// writeReports writes report in requested formats and checks it against the policy.
+func writeReports(args ExecArgs, report *report.Report) error {
 	// Json Report
 	if args.Formats.Json {
-		err = WriteJsonReportToFile(report, args)
+		err := WriteJsonReportToFile(report, args)
 		if err != nil {
 			return err
 		}
@@ -407,13 +828,69 @@ func StepReport(args ExecArgs) error {
 
 	// Html Report
 	if args.Formats.Html {
-		err = WriteHtmlReportToFile(report, args)
+		err := WriteHtmlReportToFile(report, args)
 		if err != nil {
 			return err
 		}
 		fmt.Printf("Report written to %s\n", args.HtmlReportFile)
 	}
 
+	// Policy
+	violations := policy.Check(args.Config, report)
+	if err := writePolicyReports(args, violations); err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		fmt.Println()
+		for _, v := range violations {
+			out.PrintlnWarn("%s", v)
+		}
+		return fmt.Errorf("%d policy violation(s) found", len(violations))
+	}
+
+	return nil
+}
+
+// writePolicyReports writes policy check results as JUnit and SARIF.
+func writePolicyReports(args ExecArgs, violations []policy.Violation) error {
+	if !args.Formats.Junit && !args.Formats.Sarif {
+		return nil
+	}
+
+	result := findings.FromViolations(violations)
+	locator := findings.NewLocator(args.ProjectDir)
+	locator.Annotate(result)
+
+	for _, it := range []struct {
+		enabled bool
+		file    string
+		name    string
+		write   func(w io.Writer) error
+	}{
+		{args.Formats.Junit, args.JunitReportFile, "JUnit", func(w io.Writer) error {
+			return findings.WriteJunit(w, "lampa policy", findings.PolicyRules, result)
+		}},
+		{args.Formats.Sarif, args.SarifReportFile, "SARIF", func(w io.Writer) error {
+			return findings.WriteSarif(w, findings.PolicyRules, result, locator.Fallback())
+		}},
+	} {
+		if !it.enabled {
+			continue
+		}
+		if err := utils.EnsureParentDirExists(it.file); err != nil {
+			return err
+		}
+		f, err := os.Create(it.file)
+		if err != nil {
+			return fmt.Errorf("could not create report file: %v", err)
+		}
+		err = it.write(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s report written to %s\n", it.name, it.file)
+	}
 	return nil
 }
 
@@ -473,66 +950,180 @@ func GenerateHtmlReport(r *report.Report) (string, error) {
 	return w.String(), nil
 }
 
-func collectReport(args ExecArgs, pathToAab string) (report.Report, error) {
+func collectReport(ctx context.Context, args ExecArgs, contextSegment report.ContextSegment, pathToAab string, export gradle.Export) (report.Report, error) {
 	result := report.Report{
 		Version: "stats/0.0.1",
 	}
 
-	context, err := parseContext(args)
+	result.Context = contextSegment
+
+	err := analyzeBuild(ctx, &result, args, pathToAab)
 	if err != nil {
 		return report.Report{}, err
 	}
-	result.Context = context
 
-	configurationName := args.BuildVariant + "CompileClasspath"
+	seen := map[string]bool{}
+	requests := internal.VersionRequests{}
+	for _, module := range args.Modules {
+		project, ok := export.Project(module)
+		if !ok {
+			return report.Report{}, fmt.Errorf("no dependencies were exported for module `%s`", module)
+		}
 
-	err = analyzeBuild(&result, args, pathToAab)
-	if err != nil {
-		return report.Report{}, err
+		for _, configurationName := range args.Configurations {
+			configuration, ok := project.Configuration(configurationName)
+			if !ok {
+				return report.Report{}, fmt.Errorf(
+					"configuration `%s` not found in module `%s` (available: %s)",
+					configurationName, module, strings.Join(project.ResolvableConfigurations, ", "),
+				)
+			}
+			for _, d := range configuration.Unresolved() {
+				out.PrintlnWarn("could not resolve %s (%s, %s)", d, module, configurationName)
+			}
+
+			tree := configuration.Tree()
+
+			platforms := internal.FindPlatforms(tree)
+			requests.Add(tree, module)
+
+			for _, info := range tree.Summary {
+				d := report.CoordinatedDependency{
+					Group:   info.GroupID,
+					Name:    info.ArtifactID,
+					Version: info.Version,
+				}
+				d.IsPlatform = lo.ContainsBy(platforms, func(p internal.Platform) bool {
+					return p.Coordinate() == d.Coordinate()
+				})
+				if p, ok := internal.FindManagingPlatform(platforms, info); ok {
+					d.ManagedBy = p.Coordinate()
+				}
+				if seen[d.String()] {
+					continue
+				}
+				if config.MatchesAnyCoordinate(args.Config.Ignore, d.Group, d.Name) {
+					continue
+				}
+				seen[d.String()] = true
+				result.Build.Dependencies.Compile = append(result.Build.Dependencies.Compile, d)
+			}
+		}
 	}
 
-	output, err := executeGradleTask(args, "app:dependencies", "--configuration", configurationName)
-	if err != nil {
-		return report.Report{}, fmt.Errorf("failed to execute gradlew: %v\nOutput:\n%s", err, string(output))
+	for i := range result.Build.Dependencies.Compile {
+		d := &result.Build.Dependencies.Compile[i]
+		for _, v := range requests.Versions(d.Coordinate()) {
+			d.Requested = append(d.Requested, report.VersionRequest{
+				Version:     v,
+				RequestedBy: requests.Consumers(d.Coordinate(), v),
+				Constraint:  requests.IsConstraintOnly(d.Coordinate(), v),
+			})
+		}
 	}
 
-	// fmt.Println(string(output))
+	addPomData(&result, gradlecache.New(args.GradleHome))
+	addArtifactChecksums(&result, export, gradlecache.New(args.GradleHome))
+	addRepositories(&result, export)
+	catalog.Scan(args.ProjectDir).Annotate(&result)
 
-	tree, err := internal.ParseTreeFromOutput(string(output), configurationName)
-	if err != nil {
-		return report.Report{}, fmt.Errorf("failed to parse tree: %v", err)
-	}
+	report.SortDependencies(result.Build.Dependencies.Compile)
+
+	return result, nil
+}
+
+// addPomData fills project information of dependencies from POM files in Gradle cache.
+func addPomData(result *report.Report, cache gradlecache.Cache) {
+	for i := range result.Build.Dependencies.Compile {
+		d := &result.Build.Dependencies.Compile[i]
 
-	for _, info := range tree.Summary {
-		d := report.CoordinatedDependency{
-			Group:   info.GroupID,
-			Name:    info.ArtifactID,
-			Version: info.Version,
+		pom, ok := cache.ReadPom(d.Group, d.Name, d.Version)
+		if !ok {
+			continue
+		}
+
+		d.Pom = &report.PomSegment{
+			Name:         strings.TrimSpace(pom.Name),
+			Description:  strings.Join(strings.Fields(pom.Description), " "),
+			Url:          strings.TrimSpace(pom.Url),
+			ScmUrl:       pom.ScmUrl(),
+			Organization: strings.TrimSpace(pom.Organization.Name),
+		}
+		for _, dev := range pom.Developers {
+			name := strings.TrimSpace(dev.Name)
+			if name == "" {
+				name = strings.TrimSpace(dev.Id)
+			}
+			if name != "" {
+				d.Pom.Developers = append(d.Pom.Developers, name)
+			}
 		}
-		result.Build.Dependencies.Compile = append(result.Build.Dependencies.Compile, d)
 	}
-	slices.SortFunc(result.Build.Dependencies.Compile, func(a, b report.CoordinatedDependency) int {
-		if a.Group > b.Group {
-			return 1
-		} else if a.Group < b.Group {
-			return -1
-		} else if a.Name > b.Name {
-			return 1
-		} else if a.Name < b.Name {
-			return -1
-		} else if a.Version > b.Version {
-			return 1
-		} else if a.Version < b.Version {
-			return -1
-		} else {
-			return 0
+}
+
+// addArtifactChecksums records SHA-256 of resolved artifact files.
+// Files resolved by Gradle are preferred, other files (e.g. POM) are taken from Gradle cache.
+// If cache has several copies of a file with different content, all of them are recorded as ambiguous.
+func addArtifactChecksums(result *report.Report, export gradle.Export, cache gradlecache.Cache) {
+	for i := range result.Build.Dependencies.Compile {
+		d := &result.Build.Dependencies.Compile[i]
+
+		files := []gradlecache.ArtifactFile{}
+		resolved := map[string]bool{}
+		if component, _, ok := export.Component(d.String()); ok {
+			for _, a := range component.Artifacts {
+				files = append(files, gradlecache.ArtifactFile{Name: a.Name, Path: a.Path})
+				resolved[a.Name] = true
+			}
+		}
+		for _, f := range cache.FindArtifactFiles(d.Group, d.Name, d.Version) {
+			if !resolved[f.Name] {
+				files = append(files, f)
+			}
 		}
-	})
 
-	return result, nil
+		seen := map[string]bool{}
+		hashes := map[string]int{}
+		for _, f := range files {
+			sha256, err := utils.FileSha256(f.Path)
+			if err != nil {
+				out.PrintlnWarn("could not calculate checksum of %s: %v", f.Path, err)
+				continue
+			}
+			if seen[f.Name+"/"+sha256] {
+				continue
+			}
+			seen[f.Name+"/"+sha256] = true
+			hashes[f.Name]++
+			d.Files = append(d.Files, report.ArtifactFile{
+				Name:   f.Name,
+				Sha256: sha256,
+			})
+		}
+		for j := range d.Files {
+			if hashes[d.Files[j].Name] > 1 {
+				d.Files[j].Ambiguous = true
+			}
+		}
+		for name, n := range hashes {
+			if n > 1 {
+				out.PrintlnWarn("%s: %d cached copies of %s with different content", d, n, name)
+			}
+		}
+	}
+}
+
+// addRepositories records repositories that resolved dependencies.
+func addRepositories(result *report.Report, export gradle.Export) {
+	for i := range result.Build.Dependencies.Compile {
+		d := &result.Build.Dependencies.Compile[i]
+		if repo, ok := export.RepositoryOf(d.String()); ok {
+			d.Repository = &repo
+		}
+	}
 }
 
-func parseContext(args ExecArgs) (report.ContextSegment, error) {
+func parseContext(ctx context.Context, args ExecArgs) (report.ContextSegment, error) {
 	result := report.ContextSegment{
 		Tool: report.ToolSegment{
 			Name:        "Lampa",
@@ -549,27 +1140,30 @@ func parseContext(args ExecArgs) (report.ContextSegment, error) {
 		return result, fmt.Errorf("git not found in PATH: %v", err)
 	}
 
-	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	ctx, cancel := proc.WithTimeout(ctx, args.ToolTimeout)
+	defer cancel()
+
+	cmd := proc.Command(ctx, "git", "rev-parse", "--is-inside-work-tree")
 	cmd.Dir = args.ProjectDir
 	if err := cmd.Run(); err != nil {
 		return result, nil
 	}
 
-	cmd = exec.Command("git", "rev-parse", "HEAD")
+	cmd = proc.Command(ctx, "git", "rev-parse", "HEAD")
 	cmd.Dir = args.ProjectDir
 	output, err := cmd.Output()
 	if err == nil {
 		result.Git.Commit = strings.TrimSpace(string(output))
 	}
 
-	cmd = exec.Command("git", "status", "--porcelain")
+	cmd = proc.Command(ctx, "git", "status", "--porcelain")
 	cmd.Dir = args.ProjectDir
 	output, err = cmd.Output()
 	if err == nil {
 		result.Git.IsDirty = len(strings.TrimSpace(string(output))) > 0
 	}
 
-	cmd = exec.Command("git", "describe", "--tags", "--long")
+	cmd = proc.Command(ctx, "git", "describe", "--tags", "--long")
 	cmd.Dir = args.ProjectDir
 	output, err = cmd.Output()
 	if err == nil {
@@ -589,7 +1183,7 @@ func parseContext(args ExecArgs) (report.ContextSegment, error) {
 		log.Printf("warning: git describe failed: %v", err)
 	}
 
-	cmd = exec.Command("git", "branch", "--show-current")
+	cmd = proc.Command(ctx, "git", "branch", "--show-current")
 	cmd.Dir = args.ProjectDir
 	output, err = cmd.Output()
 	if err == nil {
@@ -603,12 +1197,23 @@ type SpinnerArgs struct {
 	Msg             string
 	MsgAfterSuccess string
 	MsgAfterFail    string
+
+	// Use plain output even for interactive terminal
+	IsPlain bool
 }
 
 func DynamicSpinner[T any](args SpinnerArgs, action func() (T, error)) (*T, error) {
-	if G.UsePlainOutput {
+	return DynamicSpinnerWithProgress(args, func(progress func(string)) (T, error) {
+		return action()
+	})
+}
+
+// DynamicSpinnerWithProgress is a DynamicSpinner which action can report its current step.
+// Current step and elapsed time are shown next to the message.
+func DynamicSpinnerWithProgress[T any](args SpinnerArgs, action func(progress func(string)) (T, error)) (*T, error) {
+	if G.UsePlainOutput || args.IsPlain {
 		fmt.Println(args.Msg)
-		data, err := action()
+		data, err := action(func(string) {})
 		if err != nil {
 			fmt.Printf("✗ %s\n", args.MsgAfterFail)
 			return nil, err
@@ -628,7 +1233,34 @@ func DynamicSpinner[T any](args SpinnerArgs, action func() (T, error)) (*T, erro
 		s.Start()
 		defer s.Stop()
 
-		data, err := action()
+		start := time.Now()
+		step := ""
+		updateSuffix := func() {
+			s.Lock()
+			s.Suffix = blue(" %s %s (%s)", args.Msg, step, progress.FormatElapsed(time.Since(start)))
+			s.Unlock()
+		}
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					updateSuffix()
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		data, err := action(func(current string) {
+			s.Lock()
+			step = current
+			s.Unlock()
+			updateSuffix()
+		})
 		if err != nil {
 			s.FinalMSG = red("✗ " + args.MsgAfterFail + "\n")
 			return nil, err
@@ -638,7 +1270,7 @@ func DynamicSpinner[T any](args SpinnerArgs, action func() (T, error)) (*T, erro
 	}
 }
 
-func analyzeBuild(result *report.Report, args ExecArgs, pathToAab string) error {
+func analyzeBuild(ctx context.Context, result *report.Report, args ExecArgs, pathToAab string) error {
 	result.Build.BuildVariant = args.BuildVariant
 	result.Build.AabName = filepath.Base(pathToAab)
 	// result.Build.ApkName = filepath.Base(args.PathToApk)
@@ -684,11 +1316,14 @@ func analyzeBuild(result *report.Report, args ExecArgs, pathToAab string) error
 	// Get other data from APK
 
 	// Analyze AAB manifest using bundletool
-	cmd := exec.Command("java", "-jar", args.BundletoolPath, "dump", "manifest", "--bundle", pathToAab)
+	toolCtx, cancel := proc.WithTimeout(ctx, args.ToolTimeout)
+	defer cancel()
+	cmd := proc.Command(toolCtx, "java", "-jar", args.BundletoolPath, "dump", "manifest", "--bundle", pathToAab)
 	cmd.Dir = args.ProjectDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to analyze AAB manifest with bundletool: %v.\nReason: %s", err, string(output))
+		return proc.Wrap(toolCtx, "bundletool", args.ToolTimeout,
+			fmt.Errorf("failed to analyze AAB manifest with bundletool: %v.\nReason: %s", err, string(output)))
 	}
 	manifest := string(output)
 
@@ -707,6 +1342,12 @@ func analyzeBuild(result *report.Report, args ExecArgs, pathToAab string) error
 			MinSdkVersion    string `xml:"minSdkVersion,attr"`
 			TargetSdkVersion string `xml:"targetSdkVersion,attr"`
 		} `xml:"uses-sdk"`
+		UsesPermissions []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"uses-permission"`
+		UsesPermissionsSdk23 []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"uses-permission-sdk-23"`
 	}
 
 	var manifestData Manifest
@@ -720,8 +1361,15 @@ func analyzeBuild(result *report.Report, args ExecArgs, pathToAab string) error
 	result.Build.MinSdkVersion = manifestData.UsesSdk.MinSdkVersion
 	result.Build.TargetSdkVersion = manifestData.UsesSdk.TargetSdkVersion
 	result.Build.CompileSdkVersion = manifestData.BuildVersionCode
+	for _, p := range append(manifestData.UsesPermissions, manifestData.UsesPermissionsSdk23...) {
+		if p.Name != "" && !slices.Contains(result.Build.Permissions, p.Name) {
+			result.Build.Permissions = append(result.Build.Permissions, p.Name)
+		}
+	}
+	slices.Sort(result.Build.Permissions)
+	result.Build.PermissionsCollected = true
 
-	err = addDataFromApk(result, args, pathToAab)
+	err = addDataFromApk(ctx, result, args, pathToAab)
 	if err != nil {
 		return err
 	}
@@ -783,7 +1431,7 @@ func analyzeBuild(result *report.Report, args ExecArgs, pathToAab string) error
 	return nil
 }
 
-func addDataFromApk(result *report.Report, args ExecArgs, pathToAab string) error {
+func addDataFromApk(ctx context.Context, result *report.Report, args ExecArgs, pathToAab string) error {
 	tempDir, err := os.MkdirTemp("", fmt.Sprintf("lampa-%x", sha1.Sum([]byte(args.ProjectDir))))
 	if err != nil {
 		return fmt.Errorf("failed to create temp dir for universal APK: %w", err)
@@ -792,8 +1440,11 @@ func addDataFromApk(result *report.Report, args ExecArgs, pathToAab string) erro
 
 	universalApkPath := filepath.Join(tempDir, "universal.apk")
 
+	ctx, cancel := proc.WithTimeout(ctx, args.ToolTimeout)
+	defer cancel()
+
 	// Use bundletool to build the universal APK from the AAB
-	cmd := exec.Command(
+	cmd := proc.Command(ctx,
 		"java", "-jar", args.BundletoolPath, "build-apks",
 		"--bundle", pathToAab,
 		"--output", universalApkPath+".apks",
@@ -803,7 +1454,8 @@ func addDataFromApk(result *report.Report, args ExecArgs, pathToAab string) erro
 	cmd.Dir = args.ProjectDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to build universal APK with bundletool: %v\nOutput:\n%s", err, string(output))
+		return proc.Wrap(ctx, "bundletool", args.ToolTimeout,
+			fmt.Errorf("failed to build universal APK with bundletool: %v\nOutput:\n%s", err, string(output)))
 	}
 
 	// Extract universal.apk from the .apks file (which is a zip)
@@ -850,11 +1502,12 @@ func addDataFromApk(result *report.Report, args ExecArgs, pathToAab string) erro
 	}
 
 	// Use aapt2 to extract the application label (app name) from the APK
-	cmdAapt := exec.Command(args.AaptPath, "dump", "badging", universalApkPath)
+	cmdAapt := proc.Command(ctx, args.AaptPath, "dump", "badging", universalApkPath)
 	cmdAapt.Dir = args.ProjectDir
 	outputAapt, err := cmdAapt.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to run aapt2 on universal.apk: %v\nOutput:\n%s", err, string(outputAapt))
+		return proc.Wrap(ctx, "aapt2", args.ToolTimeout,
+			fmt.Errorf("failed to run aapt2 on universal.apk: %v\nOutput:\n%s", err, string(outputAapt)))
 	}
 
 	// Parse the output to find the application-label
@@ -902,7 +1555,7 @@ func findAaptExecutable(sdkRoot string) (string, error) {
 }
 
 func findAabFile(args ExecArgs) (string, error) {
-	bundleDir := path.Join(args.ProjectDir, "app", "build", "outputs", "bundle", args.BuildVariant)
+	bundleDir := path.Join(args.ProjectDir, args.Modules[0], "build", "outputs", "bundle", args.BuildVariant)
 
 	info, err := os.Stat(bundleDir)
 	if err != nil {
@@ -932,18 +1585,3 @@ func findAabFile(args ExecArgs) (string, error) {
 
 	return aabFilePath, nil
 }
-
-func executeGradleTask(args ExecArgs, gradleArgs ...string) ([]byte, error) {
-	cmd := exec.Command(
-		args.GradlewPath,
-		append(
-			[]string{
-				"--no-daemon", "--console",
-				"plain", "-q",
-			},
-			gradleArgs...,
-		)...,
-	)
-	cmd.Dir = args.ProjectDir
-	return cmd.CombinedOutput()
-}
@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"lampa/cmd/cli/cache"
 	"lampa/cmd/cli/collect"
 	"lampa/cmd/cli/compare"
+	"lampa/cmd/cli/outdated"
+	"lampa/cmd/cli/publish"
+	"lampa/cmd/cli/serve"
+	"lampa/cmd/cli/tui"
+	"lampa/cmd/cli/variants"
+	"lampa/cmd/cli/verification"
 	"lampa/internal/out"
-	"net/http"
 
-	"github.com/samber/lo"
 	"github.com/square/exit"
 	"github.com/urfave/cli/v3"
 )
@@ -21,8 +25,14 @@ func CreateCliCommand() *cli.Command {
 		Commands: []*cli.Command{
 			collect.CreateCliCommand(),
 			compare.CreateCliCommand(),
+			outdated.CreateCliCommand(),
+			verification.CreateCliCommand(),
+			cache.CreateCliCommand(),
+			publish.CreateCliCommand(),
+			serve.CreateCliCommand(),
+			tui.CreateCliCommand(),
+			variants.CreateCliCommand(),
 			CreateVersionCommand(),
-			// devReportCommand(),
 		},
 		CommandNotFound: handleCommandNotFound,
 	}
@@ -42,32 +52,3 @@ func handleCommandNotFound(ctx context.Context, c *cli.Command, s string) {
 
 	cli.ShowAppHelpAndExit(c, exit.UnknownSubcommand)
 }
-
-func devReportCommand() *cli.Command {
-	return &cli.Command{
-		Name: "testhtml",
-		Action: func(ctx context.Context, c *cli.Command) error {
-			srv := &http.Server{Addr: ":8080"}
-			http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-				w.Header().Set("Content-Type", "text/html")
-
-				r1 := lo.Must(compare.ReadReportFromFile("out/libretube-prev.lampa.json"))
-				// d := lo.Must(collect.GenerateHtmlReport(r1))
-
-				r2 := lo.Must(compare.ReadReportFromFile("out/libretube-next.lampa.json"))
-				// d := lo.Must(collect.GenerateHtmlReport(r2))
-
-				d := lo.Must(compare.GenerateComparingHtmlReport(r1, r2))
-
-				w.Write([]byte(d))
-			})
-			fmt.Println("HTTP server started on :8080")
-			err := srv.ListenAndServe()
-			if err != nil && err != http.ErrServerClosed {
-				fmt.Printf("HTTP server error: %v\n", err)
-				return err
-			}
-			return nil
-		},
-	}
-}
@@ -4,53 +4,137 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"lampa/internal/changelog"
+	"lampa/internal/config"
+	"lampa/internal/diff"
+	"lampa/internal/findings"
+	"lampa/internal/gradlecache"
+	"lampa/internal/policy"
 	"lampa/internal/report"
 	"lampa/internal/templates/html/compare"
+	"lampa/internal/utils"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/urfave/cli/v3"
 )
 
+const (
+	OptConfigFile = "config"
+	OptOutput     = "output"
+	OptJunit      = "junit"
+	OptSarif      = "sarif"
+	OptProjectDir = "project"
+)
+
 func CreateCliCommand() *cli.Command {
 	return &cli.Command{
-		Name:   "compare",
-		Usage:  "generate comperative report between versions",
+		Name:      "compare",
+		Usage:     "generate comperative report between versions",
+		ArgsUsage: "report1.json report2.json [report3.json...] [-o out.html]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    OptOutput,
+				Aliases: []string{"o"},
+				Usage:   "write HTML report to file (more than two reports are compared as a matrix)",
+			},
+			&cli.StringFlag{
+				Name:  OptJunit,
+				Usage: "write changes and policy violations to file as JUnit XML",
+			},
+			&cli.StringFlag{
+				Name:  OptSarif,
+				Usage: "write changes and policy violations to file as SARIF",
+			},
+			&cli.StringFlag{
+				Name:    OptProjectDir,
+				Usage:   "project directory to look up dependency declarations in (for JUnit and SARIF)",
+				Value:   ".",
+				Sources: cli.EnvVars("LAMPA_PROJECT"),
+			},
+			&cli.StringFlag{
+				Name:    OptConfigFile,
+				Usage:   "config file (by default lampa.toml/.lampa.yaml is looked up in current directory)",
+				Sources: cli.EnvVars("LAMPA_CONFIG"),
+			},
+		},
 		Action: ActionCmdCompare,
 	}
 }
 
 func ActionCmdCompare(context context.Context, cmd *cli.Command) error {
-	if cmd.NArg() != 3 {
-		return fmt.Errorf("usage: lampa report1.json report2.json out.html")
+	files := cmd.Args().Slice()
+	outFile := cmd.String(OptOutput)
+	// Legacy form: report1.json report2.json out.html
+	if outFile == "" && len(files) == 3 && strings.HasSuffix(strings.ToLower(files[2]), ".html") {
+		outFile = files[2]
+		files = files[:2]
 	}
-
-	file1, err := checkReportFile(cmd.Args().Get(0))
-	if err != nil {
-		return err
+	if len(files) < 2 {
+		return fmt.Errorf("usage: lampa compare report1.json report2.json [report3.json...] [-o out.html]")
 	}
-	file2, err := checkReportFile(cmd.Args().Get(1))
-	if err != nil {
-		return err
+	if outFile != "" && isOneOf(outFile, files) {
+		return fmt.Errorf("output file `%s` is one of compared reports", outFile)
 	}
 
-	r1, err := ReadReportFromFile(file1)
-	if err != nil {
-		return err
+	if len(files) > 2 && (cmd.IsSet(OptJunit) || cmd.IsSet(OptSarif)) {
+		return fmt.Errorf("JUnit and SARIF reports are supported only for two reports")
 	}
-	r2, err := ReadReportFromFile(file2)
+
+	cfg, err := config.LoadForCli(cmd.String(OptConfigFile), cmd.IsSet(OptConfigFile))
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Comparing releases %s...%s\n", r1.Build.VersionName, r2.Build.VersionName)
+	reports := []*report.Report{}
+	for _, file := range files {
+		file, err := checkReportFile(file)
+		if err != nil {
+			return err
+		}
+		r, err := ReadReportFromFile(file)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, r)
+	}
 
-	html, err := GenerateComparingHtmlReport(r1, r2)
+	var html string
+	if len(reports) == 2 {
+		r1, r2 := reports[0], reports[1]
+		c := CompareReports(r1, r2, cfg)
+		if c.Mode == diff.ModeVariants {
+			fmt.Printf("Comparing variants %s...%s of commit %s\n", r1.Build.BuildVariant, r2.Build.BuildVariant, r2.Context.Git.Commit)
+		} else {
+			fmt.Printf("Comparing releases %s...%s\n", r1.Build.VersionName, r2.Build.VersionName)
+		}
+		PrintSummary(r1, r2, c)
+		if err := writeFindings(cmd, cfg, r2, c); err != nil {
+			return err
+		}
+		if outFile != "" {
+			html, err = RenderComparingHtmlReport(r1, r2, c)
+		}
+	} else {
+		fmt.Printf("Comparing %d reports\n", len(reports))
+		m := diff.CompareMatrix(reports, cfg.Compare.Rules)
+		PrintMatrixSummary(m)
+		if outFile != "" {
+			html, err = RenderMatrixHtmlReport(reports, m)
+		}
+	}
 	if err != nil {
 		return err
 	}
+	if outFile == "" {
+		return nil
+	}
 
-	outFile := cmd.Args().Get(2)
+	if err := utils.EnsureParentDirExists(outFile); err != nil {
+		return err
+	}
 	outF, err := os.Create(outFile)
 	if err != nil {
 		return err
@@ -60,62 +144,23 @@ func ActionCmdCompare(context context.Context, cmd *cli.Command) error {
 	if err != nil {
 		return err
 	}
-
-	// newDeps := findNewDeps(dep1, dep2)
-	// removedDeps := findRemovedDeps(dep1, dep2)
-	// changedDeps := findChangedDeps(dep1, dep2)
-
-	// fmt.Println()
-	// fmt.Printf("Total dependencies before: %d\n", len(dep1))
-	// fmt.Printf("Total dependencies after: %d\n", len(dep2))
-
-	// fmt.Println()
-	// fmt.Printf("New dependencies:\n")
-	// for _, dep := range newDeps {
-	// 	fmt.Printf("- %s:%s: %s\n", dep.Group, dep.Name, dep.Version)
-	// }
-
-	// fmt.Println()
-	// fmt.Printf("Removed dependencies:\n")
-	// for _, dep := range removedDeps {
-	// 	fmt.Printf("- %s:%s: %s\n", dep.Group, dep.Name, dep.Version)
-	// }
-
-	// fmt.Println()
-	// fmt.Printf("Changed dependencies:\n")
-	// for _, dep := range changedDeps {
-	// 	fmt.Printf("- %s:%s: %s -> %s\n", dep.Dependency.Group, dep.Dependency.Name, dep.PrevVersion, dep.Dependency.Version)
-	// }
-
-	// fmt.Println()
+	fmt.Printf("\nReport written to %s\n", outFile)
 
 	return nil
 }
 
-type Dependency struct {
-	Group   string
-	Name    string
-	Version string
-}
-
-func parseDependencies(report report.Report) ([]Dependency, error) {
-	result := make([]Dependency, 0, len(report.Build.Dependencies.Compile))
-
-	for _, depStr := range report.Build.Dependencies.Compile {
-		parts := strings.Split(depStr.String(), ":")
-		if len(parts) == 3 {
-			result = append(result, Dependency{
-				Group:   parts[0],
-				Name:    parts[1],
-				Version: parts[2],
-			})
-		} else {
-			err := fmt.Errorf("dependency string %q does not have 3 parts (group:name:version)", depStr)
-			return []Dependency{}, err
+// isOneOf checks if `path` points to one of `files`.
+func isOneOf(path string, files []string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, file := range files {
+		if other, err := filepath.Abs(file); err == nil && other == abs {
+			return true
 		}
 	}
-
-	return result, nil
+	return false
 }
 
 func checkReportFile(path string) (string, error) {
@@ -144,23 +189,74 @@ func ReadReportFromFile(file string) (*report.Report, error) {
 	return &report, nil
 }
 
-// func readReport(file string) ([]Dependency, string, error) {
-// 	report, err := ReadReportFromFile(file)
-// 	if err != nil {
-// 		return nil, "", err
-// 	}
+// writeFindings writes comparison and policy check of the new report as JUnit and SARIF.
+func writeFindings(cmd *cli.Command, cfg config.Config, r2 *report.Report, c diff.Comparison) error {
+	junitFile, sarifFile := cmd.String(OptJunit), cmd.String(OptSarif)
+	if junitFile == "" && sarifFile == "" {
+		return nil
+	}
+
+	rules := append([]findings.Rule{}, findings.CompareRules...)
+	result := findings.FromComparison(c)
+	if len(cfg.Policy.Deny) > 0 || cfg.Policy.DenyUnstable {
+		rules = append(rules, findings.PolicyRules...)
+		result = append(result, findings.FromViolations(policy.Check(cfg, r2))...)
+	}
+	locator := findings.NewLocator(utils.TryResolveFsPath(cmd.String(OptProjectDir)))
+	locator.Annotate(result)
+
+	if junitFile != "" {
+		err := writeFile(junitFile, func(w io.Writer) error {
+			return findings.WriteJunit(w, "lampa compare", rules, result)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\nJUnit report written to %s\n", junitFile)
+	}
+	if sarifFile != "" {
+		err := writeFile(sarifFile, func(w io.Writer) error {
+			return findings.WriteSarif(w, rules, result, locator.Fallback())
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("SARIF report written to %s\n", sarifFile)
+	}
+	return nil
+}
+
+func writeFile(path string, write func(w io.Writer) error) error {
+	if err := utils.EnsureParentDirExists(path); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create `%s`: %v", path, err)
+	}
+	defer f.Close()
+	return write(f)
+}
 
-// 	dep, err := parseDependencies(*report)
-// 	if err != nil {
-// 		return nil, "", fmt.Errorf("could not parse %s as report.Report: %v", file, err)
-// 	}
+// CompareReports compares reports and adds release notes links to changed dependencies.
+func CompareReports(r1 *report.Report, r2 *report.Report, cfg config.Config) diff.Comparison {
+	c := diff.Compare(r1, r2, cfg)
+	c.Dependencies.AddChangelogLinks(changelog.DefaultChain(cfg, gradlecache.New(gradlecache.DefaultRoot())))
+	return c
+}
 
-// 	return dep, report.Context.Git.Commit, err
-// }
+func RenderComparingHtmlReport(r1 *report.Report, r2 *report.Report, c diff.Comparison) (string, error) {
+	w := &strings.Builder{}
+	err := compare.CompareHtml(r1, r2, c).Render(context.Background(), w)
+	if err != nil {
+		return "", err
+	}
+	return w.String(), nil
+}
 
-func GenerateComparingHtmlReport(r1 *report.Report, r2 *report.Report) (string, error) {
+func RenderMatrixHtmlReport(reports []*report.Report, m diff.Matrix) (string, error) {
 	w := &strings.Builder{}
-	err := compare.CompareHtml(r1, r2).Render(context.Background(), w)
+	err := compare.MatrixHtml(reports, m).Render(context.Background(), w)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,199 @@
+package compare
+
+import (
+	"fmt"
+	"lampa/internal/diff"
+	"lampa/internal/report"
+	"lampa/internal/templates"
+	"lampa/internal/templates/html/compare"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+
+	. "lampa/internal/globals"
+)
+
+type summaryColors struct {
+	header     func(a ...any) string
+	added      func(a ...any) string
+	removed    func(a ...any) string
+	upgraded   func(a ...any) string
+	downgraded func(a ...any) string
+	changed    func(a ...any) string
+	warning    func(a ...any) string
+}
+
+func newSummaryColors() summaryColors {
+	if G.UsePlainOutput {
+		return summaryColors{fmt.Sprint, fmt.Sprint, fmt.Sprint, fmt.Sprint, fmt.Sprint, fmt.Sprint, fmt.Sprint}
+	}
+	return summaryColors{
+		header:     color.New(color.Bold).SprintFunc(),
+		added:      color.New(color.FgGreen).SprintFunc(),
+		removed:    color.New(color.FgRed).SprintFunc(),
+		upgraded:   color.New(color.FgBlue).SprintFunc(),
+		downgraded: color.New(color.FgYellow).SprintFunc(),
+		changed:    color.New(color.FgMagenta).SprintFunc(),
+		warning:    color.New(color.FgYellow).SprintFunc(),
+	}
+}
+
+// PrintSummary prints difference between two reports to the console.
+func PrintSummary(r1 *report.Report, r2 *report.Report, c diff.Comparison) {
+	colors := newSummaryColors()
+	labels := compare.NewLabels(r1, r2, c.Mode)
+
+	// Build
+	fmt.Println()
+	printChange("Size", templates.FormatFileSize(r1.Build.AabSize), templates.FormatFileSize(r2.Build.AabSize))
+	if delta, ok := sizeDelta(r1.Build.AabSize, r2.Build.AabSize); ok && delta != "" {
+		fmt.Printf("  (%s)\n", delta)
+	}
+	printChange("Min SDK", r1.Build.MinSdkVersion, r2.Build.MinSdkVersion)
+	printChange("Target SDK", r1.Build.TargetSdkVersion, r2.Build.TargetSdkVersion)
+	printChange("Compile SDK", r1.Build.CompileSdkVersion, r2.Build.CompileSdkVersion)
+
+	// Dependencies
+	deps := c.Dependencies
+	fmt.Println()
+	fmt.Println(colors.header(fmt.Sprintf("Dependencies: %d → %d",
+		len(r1.Build.Dependencies.Compile), len(r2.Build.Dependencies.Compile))))
+	printDeps(labels.New, deps.New, "+", colors.added)
+	printDeps(labels.Removed, deps.Removed, "-", colors.removed)
+	printDeps(labels.Upgraded, deps.Upgraded, "^", colors.upgraded)
+	printDeps(labels.Downgraded, deps.Downgraded, "v", colors.downgraded)
+	printDeps(labels.Changed, deps.Changed, "~", colors.changed)
+	fmt.Printf("%s: %d\n", labels.Unchanged, len(deps.Unchanged))
+
+	// Permissions
+	if !c.Permissions.IsEmpty() {
+		fmt.Println()
+		fmt.Println(colors.header("Permissions"))
+		for _, p := range c.Permissions.Added {
+			fmt.Println(colors.warning("+ " + p))
+		}
+		for _, p := range c.Permissions.Removed {
+			fmt.Println("- " + p)
+		}
+	}
+
+	// Version catalog
+	if len(c.Catalog) > 0 {
+		fmt.Println()
+		fmt.Println(colors.header(fmt.Sprintf("Version catalog: %d changed", len(c.Catalog))))
+		for _, it := range c.Catalog {
+			line := fmt.Sprintf("%s: %s", it.Accessor, it.Summary())
+			switch {
+			case it.IsAdded():
+				fmt.Println(colors.added("+ " + line))
+			case it.IsRemoved():
+				fmt.Println(colors.removed("- " + line))
+			default:
+				fmt.Println(colors.changed("~ " + line))
+			}
+		}
+	}
+
+	// Findings
+	findings := []string{}
+	if n := len(c.Conflicts); n > 0 {
+		findings = append(findings, fmt.Sprintf("Version conflicts: %d", n))
+	}
+	if n := len(c.Unstable); n > 0 {
+		findings = append(findings, fmt.Sprintf("Unstable dependencies: %d", n))
+	}
+	if n := len(c.ChecksumChanges); n > 0 {
+		findings = append(findings, fmt.Sprintf("Same version, different bytes: %d", n))
+	}
+	if n := len(c.RepositoryChanges) + len(c.NewRepositories); n > 0 {
+		findings = append(findings, fmt.Sprintf("Repository changes: %d", n))
+	}
+	if len(findings) > 0 {
+		fmt.Println()
+		for _, f := range findings {
+			fmt.Println(colors.warning(f))
+		}
+	}
+}
+
+// PrintMatrixSummary prints dependencies that differ between reports.
+func PrintMatrixSummary(m diff.Matrix) {
+	colors := newSummaryColors()
+
+	fmt.Println()
+	fmt.Println(colors.header(strings.Join(m.Columns, " | ")))
+	for _, row := range m.Rows {
+		if !row.HasChanges() {
+			continue
+		}
+		cells := []string{}
+		for _, cell := range row.Cells {
+			version := cell.Version
+			if version == "" {
+				version = "—"
+			}
+			switch cell.Change {
+			case diff.CellNew:
+				version = colors.added(version)
+			case diff.CellRemoved:
+				version = colors.removed("—")
+			case diff.CellUpgraded:
+				version = colors.upgraded(version)
+			case diff.CellDowngraded:
+				version = colors.downgraded(version)
+			case diff.CellChanged:
+				version = colors.changed(version)
+			}
+			cells = append(cells, version)
+		}
+		fmt.Printf("%s: %s\n", row.Name(), strings.Join(cells, " | "))
+	}
+	fmt.Printf("\nChanged: %d of %d\n", m.ChangedRows(), len(m.Rows))
+}
+
+func printChange(name string, before string, after string) {
+	if before == "" && after == "" {
+		return
+	}
+	if before == after {
+		fmt.Printf("%s: %s\n", name, after)
+	} else {
+		fmt.Printf("%s: %s → %s\n", name, before, after)
+	}
+}
+
+func printDeps(label string, deps []diff.Dep, mark string, paint func(a ...any) string) {
+	fmt.Printf("%s: %d\n", label, len(deps))
+	for _, d := range deps {
+		line := fmt.Sprintf("  %s %s: %s", mark, d.Name(), d.Version)
+		if d.Alias != "" {
+			line += fmt.Sprintf(" (%s)", d.Alias)
+		}
+		if len(d.Members) > 0 {
+			line += fmt.Sprintf(" (%d artifacts)", len(d.Members))
+		}
+		fmt.Println(paint(line))
+	}
+}
+
+// sizeDelta returns signed difference between file sizes (empty if sizes are equal).
+func sizeDelta(before string, after string) (string, bool) {
+	b, err := strconv.Atoi(before)
+	if err != nil {
+		return "", false
+	}
+	a, err := strconv.Atoi(after)
+	if err != nil {
+		return "", false
+	}
+	delta := a - b
+	switch {
+	case delta == 0:
+		return "", true
+	case delta > 0:
+		return "+" + templates.FormatFileSize(strconv.Itoa(delta)), true
+	default:
+		return "-" + templates.FormatFileSize(strconv.Itoa(-delta)), true
+	}
+}
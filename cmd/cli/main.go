@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"unicode/utf8"
 
 	. "lampa/internal/globals"
@@ -27,8 +29,17 @@ func main() {
 	}
 	printHeader()
 
+	// Ctrl-C cancels running command (and stops external tools it has started).
+	// Second Ctrl-C terminates immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
 	cmd := CreateCliCommand()
-	err := cmd.Run(context.Background(), os.Args)
+	err := cmd.Run(ctx, os.Args)
+	stop()
 	if err != nil {
 		// if e, ok := err.(exit.Error); ok {
 		// 	err = e.Cause
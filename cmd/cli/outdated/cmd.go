@@ -0,0 +1,173 @@
+package outdated
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"lampa/cmd/cli/collect"
+	"lampa/cmd/cli/compare"
+	"lampa/internal/maven"
+	"lampa/internal/report"
+	"lampa/internal/utils"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v3"
+
+	. "lampa/internal/globals"
+)
+
+const (
+	OptRepositories = "repo"
+	OptJsonFile     = "json"
+	OptHtmlFile     = "html"
+)
+
+func CreateCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "outdated",
+		Usage:     "find newer versions of report dependencies in local Maven repositories (offline)",
+		ArgsUsage: "<report.json>",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    OptRepositories,
+				Usage:   "local repository or mirror in Maven layout (e.g. ~/.m2/repository or Nexus export)",
+				Value:   []string{"~/.m2/repository"},
+				Sources: cli.EnvVars("LAMPA_MAVEN_REPOS"),
+			},
+			&cli.StringFlag{
+				Name:  OptJsonFile,
+				Usage: "write report with latest versions to JSON file (can be the same as input)",
+			},
+			&cli.StringFlag{
+				Name:  OptHtmlFile,
+				Usage: "write HTML report with \"Behind latest\" section",
+			},
+		},
+		Action: CmdActionOutdated,
+	}
+}
+
+func CmdActionOutdated(ctx context.Context, cmd *cli.Command) error {
+	if cmd.NArg() != 1 {
+		return fmt.Errorf("usage: lampa outdated report.json [--repo dir] [--json out.json] [--html out.html]")
+	}
+
+	r, err := compare.ReadReportFromFile(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	repos := []string{}
+	for _, repo := range cmd.StringSlice(OptRepositories) {
+		repo = utils.TryResolveFsPath(repo)
+		if !utils.IsDir(repo) {
+			return fmt.Errorf("repository directory `%s` does not exist", repo)
+		}
+		repos = append(repos, repo)
+	}
+
+	FillLatestVersions(r, repos)
+	PrintOutdated(r)
+
+	if file := cmd.String(OptJsonFile); file != "" {
+		if err := writeJson(r, utils.TryResolveFsPath(file)); err != nil {
+			return err
+		}
+		fmt.Printf("\nReport written to %s\n", file)
+	}
+	if file := cmd.String(OptHtmlFile); file != "" {
+		if err := writeHtml(r, utils.TryResolveFsPath(file)); err != nil {
+			return err
+		}
+		fmt.Printf("HTML report written to %s\n", file)
+	}
+
+	return nil
+}
+
+// FillLatestVersions looks up the newest versions of all dependencies in local repositories.
+func FillLatestVersions(r *report.Report, repos []string) {
+	for i := range r.Build.Dependencies.Compile {
+		d := &r.Build.Dependencies.Compile[i]
+
+		stable, preRelease := maven.LatestVersions(maven.FindVersions(repos, d.Group, d.Name))
+		if stable == "" && preRelease == "" {
+			d.Latest = nil
+			continue
+		}
+		d.Latest = &report.LatestVersions{
+			Stable:     stable,
+			PreRelease: preRelease,
+		}
+	}
+}
+
+func PrintOutdated(r *report.Report) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	if G.UsePlainOutput {
+		yellow = fmt.Sprint
+		green = fmt.Sprint
+	}
+
+	total := 0
+	unknown := 0
+	for _, d := range r.Build.Dependencies.Compile {
+		if d.Latest == nil {
+			unknown++
+			continue
+		}
+		target, ok := d.LatestTarget()
+		if !ok {
+			continue
+		}
+
+		total++
+		line := fmt.Sprintf("- %s: %s → %s", d.Coordinate(), d.Version, yellow(target))
+		if d.Latest.PreRelease != "" && d.Latest.PreRelease != target {
+			line += fmt.Sprintf(" (pre-release: %s)", d.Latest.PreRelease)
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	if total == 0 {
+		fmt.Println(green("All dependencies are up to date."))
+	} else {
+		fmt.Printf("Behind latest: %d\n", total)
+	}
+	if unknown > 0 {
+		fmt.Printf("Not found in repositories: %d\n", unknown)
+	}
+}
+
+func writeJson(r *report.Report, file string) error {
+	if err := utils.EnsureParentDirExists(file); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal report: %v", err)
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("could not write report: %v", err)
+	}
+	return nil
+}
+
+func writeHtml(r *report.Report, file string) error {
+	if err := utils.EnsureParentDirExists(file); err != nil {
+		return err
+	}
+
+	html, err := collect.GenerateHtmlReport(r)
+	if err != nil {
+		return fmt.Errorf("could not generate HTML report: %v", err)
+	}
+	if err := os.WriteFile(file, []byte(html), 0644); err != nil {
+		return fmt.Errorf("could not write HTML report: %v", err)
+	}
+	return nil
+}
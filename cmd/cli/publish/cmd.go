@@ -0,0 +1,170 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"lampa/cmd/cli/compare"
+	"lampa/internal/config"
+	"lampa/internal/publish"
+	"lampa/internal/utils"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	OptForge       = "forge"
+	OptApiUrl      = "api-url"
+	OptRepo        = "repo"
+	OptPullRequest = "pr"
+	OptToken       = "token"
+	OptArtifactUrl = "artifact-url"
+	OptOutput      = "output"
+	OptDryRun      = "dry-run"
+	OptConfigFile  = "config"
+)
+
+func CreateCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "publish",
+		Usage:     "post (or update) comparison summary comment in pull request",
+		ArgsUsage: "report1.json report2.json",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    OptForge,
+				Usage:   "forge: " + strings.Join(publish.Forges, ", ") + " (detected in CI by default)",
+				Sources: cli.EnvVars("LAMPA_FORGE"),
+			},
+			&cli.StringFlag{
+				Name:    OptApiUrl,
+				Usage:   "API base URL (for self-hosted instances)",
+				Sources: cli.EnvVars("LAMPA_FORGE_API_URL"),
+			},
+			&cli.StringFlag{
+				Name:    OptRepo,
+				Usage:   "repository (`owner/name` or GitLab project id)",
+				Sources: cli.EnvVars("LAMPA_FORGE_REPO"),
+			},
+			&cli.IntFlag{
+				Name:    OptPullRequest,
+				Usage:   "pull (merge) request number",
+				Sources: cli.EnvVars("LAMPA_PR"),
+			},
+			&cli.StringFlag{
+				Name:    OptToken,
+				Usage:   "API token",
+				Sources: cli.EnvVars("LAMPA_FORGE_TOKEN"),
+			},
+			&cli.StringFlag{
+				Name:    OptArtifactUrl,
+				Usage:   "link to uploaded HTML report",
+				Sources: cli.EnvVars("LAMPA_ARTIFACT_URL"),
+			},
+			&cli.StringFlag{
+				Name:    OptOutput,
+				Aliases: []string{"o"},
+				Usage:   "also write HTML report to file (to upload it as an artifact)",
+			},
+			&cli.BoolFlag{
+				Name:  OptDryRun,
+				Usage: "print comment instead of posting it",
+			},
+			&cli.StringFlag{
+				Name:    OptConfigFile,
+				Usage:   "config file (by default lampa.toml/.lampa.yaml is looked up in current directory)",
+				Sources: cli.EnvVars("LAMPA_CONFIG"),
+			},
+		},
+		Action: ActionCmdPublish,
+	}
+}
+
+func ActionCmdPublish(ctx context.Context, cmd *cli.Command) error {
+	if cmd.NArg() != 2 {
+		return fmt.Errorf("usage: lampa publish report1.json report2.json")
+	}
+
+	cfg, err := config.LoadForCli(cmd.String(OptConfigFile), cmd.IsSet(OptConfigFile))
+	if err != nil {
+		return err
+	}
+
+	r1, err := compare.ReadReportFromFile(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	r2, err := compare.ReadReportFromFile(cmd.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	c := compare.CompareReports(r1, r2, cfg)
+
+	if outFile := cmd.String(OptOutput); outFile != "" {
+		html, err := compare.RenderComparingHtmlReport(r1, r2, c)
+		if err != nil {
+			return err
+		}
+		if err := utils.EnsureParentDirExists(outFile); err != nil {
+			return err
+		}
+		if err := os.WriteFile(outFile, []byte(html), 0644); err != nil {
+			return fmt.Errorf("could not write `%s`: %v", outFile, err)
+		}
+		fmt.Printf("Report written to %s\n", outFile)
+	}
+
+	body := publish.Markdown(r1, r2, c, cmd.String(OptArtifactUrl))
+	if cmd.Bool(OptDryRun) {
+		fmt.Print(body)
+		return nil
+	}
+
+	target := resolveTarget(cmd, cfg.Publish)
+	if target.Forge == "" {
+		return fmt.Errorf("forge is not set and can't be detected (use --%s)", OptForge)
+	}
+	if target.PR <= 0 {
+		return fmt.Errorf("pull request number is not set (use --%s)", OptPullRequest)
+	}
+
+	forge, err := publish.New(target.Forge, target.Options)
+	if err != nil {
+		return err
+	}
+	result, err := publish.Publish(ctx, forge, target.PR, body)
+	if err != nil {
+		return err
+	}
+	switch result {
+	case publish.Created:
+		fmt.Printf("Comment posted to %s#%d\n", target.Repo, target.PR)
+	case publish.Updated:
+		fmt.Printf("Comment updated in %s#%d\n", target.Repo, target.PR)
+	case publish.Unchanged:
+		fmt.Printf("Comment in %s#%d is up to date\n", target.Repo, target.PR)
+	}
+	return nil
+}
+
+// resolveTarget merges flags, config file and values detected in CI (in order of priority).
+func resolveTarget(cmd *cli.Command, cfg config.PublishConfig) publish.Target {
+	pick := func(opt string, fromConfig string) string {
+		if cmd.IsSet(opt) {
+			return cmd.String(opt)
+		}
+		return fromConfig
+	}
+	explicit := publish.Target{
+		Forge: pick(OptForge, cfg.Forge),
+		Options: publish.Options{
+			ApiUrl: pick(OptApiUrl, cfg.ApiUrl),
+			Repo:   pick(OptRepo, cfg.Repo),
+			Token:  pick(OptToken, ""),
+		},
+	}
+	if cmd.IsSet(OptPullRequest) {
+		explicit.PR = int(cmd.Int(OptPullRequest))
+	}
+	return publish.Resolve(publish.DetectCi(os.Getenv), explicit)
+}
@@ -0,0 +1,79 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lampa/internal/config"
+	"lampa/internal/server"
+	"lampa/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	OptAddress    = "addr"
+	OptConfigFile = "config"
+)
+
+const (
+	DefaultAddress = "localhost:8080"
+)
+
+func CreateCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "serve",
+		Usage:     "browse and compare reports of the directory in web browser",
+		ArgsUsage: "<reports-dir>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    OptAddress,
+				Usage:   "address to listen on (use ':8080' to make it available in the network)",
+				Value:   DefaultAddress,
+				Sources: cli.EnvVars("LAMPA_SERVE_ADDR"),
+			},
+			&cli.StringFlag{
+				Name:    OptConfigFile,
+				Usage:   "config file with compare rules (by default lampa.toml/.lampa.yaml is looked up in current directory)",
+				Sources: cli.EnvVars("LAMPA_CONFIG"),
+			},
+		},
+		Action: CmdActionServe,
+	}
+}
+
+func CmdActionServe(ctx context.Context, cmd *cli.Command) error {
+	if cmd.NArg() != 1 {
+		return fmt.Errorf("usage: lampa serve <reports-dir>")
+	}
+	dir := utils.TryResolveFsPath(cmd.Args().First())
+	if !utils.IsDir(dir) {
+		return fmt.Errorf("`%s` is not a directory", dir)
+	}
+
+	cfg, err := config.LoadForCli(cmd.String(OptConfigFile), cmd.IsSet(OptConfigFile))
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:    cmd.String(OptAddress),
+		Handler: server.New(dir, cfg).Handler(),
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving reports from %s on http://%s\n", dir, srv.Addr)
+	fmt.Println("Press Ctrl-C to stop.")
+	err = srv.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP server error: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"lampa/cmd/cli/compare"
+	"lampa/internal/config"
+	"lampa/internal/report"
+	"lampa/internal/tui"
+
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	OptConfigFile = "config"
+)
+
+func CreateCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "tui",
+		Usage:     "explore report (or difference between two reports) in terminal",
+		ArgsUsage: "<report.json> [other.json]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    OptConfigFile,
+				Usage:   "config file with compare rules (by default lampa.toml/.lampa.yaml is looked up in current directory)",
+				Sources: cli.EnvVars("LAMPA_CONFIG"),
+			},
+		},
+		Action: CmdActionTui,
+	}
+}
+
+func CmdActionTui(ctx context.Context, cmd *cli.Command) error {
+	if cmd.NArg() < 1 || cmd.NArg() > 2 {
+		return fmt.Errorf("usage: lampa tui <report.json> [other.json]")
+	}
+
+	cfg, err := config.LoadForCli(cmd.String(OptConfigFile), cmd.IsSet(OptConfigFile))
+	if err != nil {
+		return err
+	}
+
+	r, err := compare.ReadReportFromFile(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	var other *report.Report
+	if cmd.NArg() == 2 {
+		other, err = compare.ReadReportFromFile(cmd.Args().Get(1))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tui.Run(ctx, tui.NewModel(r, other, cfg))
+}
@@ -0,0 +1,96 @@
+package variants
+
+import (
+	"context"
+	"fmt"
+	"lampa/internal/gradle"
+	"lampa/internal/progress"
+	"lampa/internal/utils"
+	"path"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	OptProjectDir = "project"
+	OptModule     = "module"
+)
+
+const (
+	DefaultModule = "app"
+)
+
+// Number of Gradle output lines printed on failure
+const maxFailureLines = 30
+
+func CreateCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "variants",
+		Usage: "list build variants, product flavors and build types of the application",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    OptProjectDir,
+				Usage:   "project directory root",
+				Value:   ".",
+				Sources: cli.EnvVars("LAMPA_PROJECT"),
+			},
+			&cli.StringFlag{
+				Name:  OptModule,
+				Usage: "Gradle module of the application",
+				Value: DefaultModule,
+			},
+		},
+		Action: CmdActionVariants,
+	}
+}
+
+func CmdActionVariants(ctx context.Context, cmd *cli.Command) error {
+	projectDir := utils.TryResolveFsPath(cmd.String(OptProjectDir))
+	module := strings.TrimSpace(cmd.String(OptModule))
+
+	gradlewPath := path.Join(projectDir, "gradlew")
+	if !utils.FileExists(gradlewPath) {
+		return fmt.Errorf("%s does not exist", gradlewPath)
+	}
+
+	runner := gradle.Runner{
+		GradlewPath: gradlewPath,
+		ProjectDir:  projectDir,
+	}
+	result, output, err := runner.ListVariants(ctx, module)
+	if err != nil {
+		if output != nil {
+			summary := progress.FailureSummary(string(output), maxFailureLines)
+			return fmt.Errorf("failed to list build variants: %v\n%s", err, strings.Join(summary, "\n"))
+		}
+		return err
+	}
+
+	fmt.Printf("Project: %s\n", result.Project)
+
+	fmt.Println("\nVariants:")
+	for _, v := range result.Variants {
+		fmt.Printf("  %s\n", v.Name)
+	}
+
+	if len(result.Flavors) > 0 {
+		fmt.Println("\nProduct flavors:")
+		for _, f := range result.Flavors {
+			if f.Dimension != "" {
+				fmt.Printf("  %s (%s)\n", f.Name, f.Dimension)
+			} else {
+				fmt.Printf("  %s\n", f.Name)
+			}
+		}
+	}
+
+	if len(result.BuildTypes) > 0 {
+		fmt.Println("\nBuild types:")
+		for _, t := range result.BuildTypes {
+			fmt.Printf("  %s\n", t)
+		}
+	}
+
+	return nil
+}
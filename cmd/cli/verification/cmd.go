@@ -0,0 +1,53 @@
+package verification
+
+import (
+	"context"
+	"fmt"
+	"lampa/cmd/cli/compare"
+	"lampa/internal/utils"
+	"lampa/internal/verification"
+	"os"
+
+	"github.com/urfave/cli/v3"
+)
+
+func CreateCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "verification-metadata",
+		Usage:     "generate Gradle dependency verification file from report checksums",
+		ArgsUsage: "<report.json> <verification-metadata.xml>",
+		Action:    CmdActionVerificationMetadata,
+	}
+}
+
+func CmdActionVerificationMetadata(ctx context.Context, cmd *cli.Command) error {
+	if cmd.NArg() != 2 {
+		return fmt.Errorf("usage: lampa verification-metadata report.json gradle/verification-metadata.xml")
+	}
+
+	r, err := compare.ReadReportFromFile(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	metadata := verification.FromReport(r)
+	if len(metadata.Components) == 0 {
+		return fmt.Errorf("report has no artifact checksums")
+	}
+
+	data, err := metadata.Marshal()
+	if err != nil {
+		return fmt.Errorf("could not generate verification metadata: %v", err)
+	}
+
+	outFile := utils.TryResolveFsPath(cmd.Args().Get(1))
+	if err := utils.EnsureParentDirExists(outFile); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outFile, data, 0644); err != nil {
+		return fmt.Errorf("could not write %s: %v", outFile, err)
+	}
+
+	fmt.Printf("Verification metadata for %d components written to %s\n", len(metadata.Components), outFile)
+	return nil
+}
@@ -3,14 +3,15 @@ module lampa
 go 1.24.1
 
 require (
-	github.com/Masterminds/semver/v3 v3.4.0
+	github.com/BurntSushi/toml v1.5.0
 	github.com/a-h/templ v0.3.906
 	github.com/briandowns/spinner v1.23.2
 	github.com/fatih/color v1.18.0
 	github.com/samber/lo v1.51.0
 	github.com/square/exit v1.3.0
 	github.com/urfave/cli/v3 v3.3.8
-	golang.org/x/text v0.24.0
+	golang.org/x/term v0.31.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -26,7 +27,7 @@ require (
 	golang.org/x/net v0.39.0 // indirect
 	golang.org/x/sync v0.13.0 // indirect
 	golang.org/x/sys v0.32.0 // indirect
-	golang.org/x/term v0.31.0 // indirect
+	golang.org/x/text v0.24.0 // indirect
 	golang.org/x/tools v0.32.0 // indirect
 )
 
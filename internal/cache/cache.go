@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"lampa/internal/report"
+	"lampa/internal/utils"
+)
+
+const EnvCacheDir = "LAMPA_CACHE_DIR"
+
+// Suffix of entry files. Cache directory could be shared with other files (e.g. reports),
+// so only files with this suffix are considered to be entries.
+const entrySuffix = ".lampa-cache.json"
+
+// DefaultDir returns cache location (`~/.cache/lampa` on Linux).
+func DefaultDir() string {
+	if dir := strings.TrimSpace(os.Getenv(EnvCacheDir)); dir != "" {
+		return utils.TryResolveFsPath(dir)
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "lampa")
+	}
+	return utils.TryResolveFsPath("~/.cache/lampa")
+}
+
+// Key identifies collected report. Reports are cached only for clean git working tree.
+type Key struct {
+	Commit         string
+	Variant        string
+	Modules        []string
+	Configurations []string
+	// Dependencies excluded from the report
+	Ignore []string
+	// Lampa version (with build commit)
+	ToolVersion string
+}
+
+// Hash returns content address of the key.
+func (self Key) Hash() string {
+	data, _ := json.Marshal(self)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Entry is a cached report with its key.
+type Entry struct {
+	Key       Key
+	CreatedAt time.Time
+	Report    report.Report
+}
+
+// EntryInfo describes cached report without loading it.
+type EntryInfo struct {
+	Hash      string
+	Key       Key
+	CreatedAt time.Time
+	Size      int64
+}
+
+// Cache stores collected reports in a directory.
+type Cache struct {
+	Dir string
+}
+
+func New(dir string) Cache {
+	return Cache{Dir: dir}
+}
+
+func (self Cache) path(hash string) string {
+	return filepath.Join(self.Dir, hash+entrySuffix)
+}
+
+// entries returns paths of entry files.
+func (self Cache) entries() ([]string, error) {
+	return filepath.Glob(filepath.Join(self.Dir, "*"+entrySuffix))
+}
+
+// Get returns cached report for the key.
+func (self Cache) Get(key Key) (Entry, bool) {
+	entry, err := self.read(self.path(key.Hash()))
+	if err != nil || entry.Key.Hash() != key.Hash() {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Put stores report for the key.
+func (self Cache) Put(key Key, r report.Report) error {
+	if err := os.MkdirAll(self.Dir, 0755); err != nil {
+		return fmt.Errorf("could not create cache directory `%s`: %v", self.Dir, err)
+	}
+
+	data, err := json.Marshal(Entry{
+		Key:       key,
+		CreatedAt: time.Now().UTC(),
+		Report:    r,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Write to temp file first, so interrupted write doesn't leave broken entry
+	path := self.path(key.Hash())
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("could not write cache entry `%s`: %v", tempPath, err)
+	}
+	return os.Rename(tempPath, path)
+}
+
+// List returns all cached entries (newest first).
+func (self Cache) List() ([]EntryInfo, error) {
+	files, err := self.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []EntryInfo{}
+	for _, file := range files {
+		entry, ok := self.readEntry(file)
+		if !ok {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		result = append(result, EntryInfo{
+			Hash:      entry.Key.Hash(),
+			Key:       entry.Key,
+			CreatedAt: entry.CreatedAt,
+			Size:      info.Size(),
+		})
+	}
+
+	slices.SortFunc(result, func(a, b EntryInfo) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return result, nil
+}
+
+// Prune removes entries older than `maxAge` (all entries if it is not positive).
+// Files that are not entries of this cache are never removed. Returns number of removed entries.
+func (self Cache) Prune(maxAge time.Duration) (int, error) {
+	files, err := self.entries()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		entry, ok := self.readEntry(file)
+		if !ok {
+			continue
+		}
+		if maxAge > 0 && time.Since(entry.CreatedAt) < maxAge {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			return removed, fmt.Errorf("could not remove `%s`: %v", file, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// readEntry reads entry file, it is valid only if its name matches hash of the key.
+func (self Cache) readEntry(path string) (Entry, bool) {
+	entry, err := self.read(path)
+	if err != nil || filepath.Base(path) != entry.Key.Hash()+entrySuffix {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+func (self Cache) read(path string) (Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry := Entry{}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lampa/internal/report"
+)
+
+func TestCache_PutGet(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "lampa"))
+	key := Key{Commit: "abc", Variant: "release", Modules: []string{"app"}, ToolVersion: "1.0"}
+
+	if _, ok := c.Get(key); ok {
+		t.Fatalf("Expected empty cache")
+	}
+
+	r := report.Report{Version: "stats/0.0.1"}
+	r.Build.VersionName = "1.2.3"
+	if err := c.Put(key, r); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+
+	entry, ok := c.Get(key)
+	if !ok || entry.Report.Build.VersionName != "1.2.3" {
+		t.Errorf("Unexpected cached entry: %#v", entry)
+	}
+
+	other := key
+	other.Variant = "debug"
+	if _, ok := c.Get(other); ok {
+		t.Errorf("Expected cache miss for another variant")
+	}
+	other = key
+	other.Modules = []string{"app", "lib"}
+	if _, ok := c.Get(other); ok {
+		t.Errorf("Expected cache miss for another module set")
+	}
+}
+
+func TestCache_ListPrune(t *testing.T) {
+	c := New(t.TempDir())
+	old := Key{Commit: "old"}
+	recent := Key{Commit: "recent"}
+	c.Put(old, report.Report{})
+	c.Put(recent, report.Report{})
+	os.WriteFile(filepath.Join(c.Dir, "broken"+entrySuffix), []byte("{"), 0644)
+	// Cache directory shared with reports
+	data, _ := json.Marshal(report.Report{Version: "stats/0.0.1"})
+	os.WriteFile(filepath.Join(c.Dir, "report.json"), data, 0644)
+	mismatched, _ := json.Marshal(Entry{Key: Key{Commit: "other"}})
+	os.WriteFile(filepath.Join(c.Dir, "mismatched"+entrySuffix), mismatched, 0644)
+
+	// Make the first entry old
+	entry, _ := c.Get(old)
+	entry.CreatedAt = time.Now().Add(-48 * time.Hour)
+	rewriteEntry(t, c, entry)
+
+	entries, err := c.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Key.Commit != "recent" || entries[1].Key.Commit != "old" {
+		t.Errorf("Unexpected entries: %#v", entries)
+	}
+
+	removed, err := c.Prune(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected old entry to be removed, removed: %d", removed)
+	}
+	if _, ok := c.Get(recent); !ok {
+		t.Errorf("Expected recent entry to be kept")
+	}
+
+	removed, _ = c.Prune(0)
+	if removed != 1 {
+		t.Errorf("Expected all entries to be removed, removed: %d", removed)
+	}
+	for _, name := range []string{"broken" + entrySuffix, "report.json", "mismatched" + entrySuffix} {
+		if _, err := os.Stat(filepath.Join(c.Dir, name)); err != nil {
+			t.Errorf("Expected `%s` that is not an entry to be kept: %v", name, err)
+		}
+	}
+}
+
+func rewriteEntry(t *testing.T, c Cache, entry Entry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(c.path(entry.Key.Hash()), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,240 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"lampa/internal/report"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Catalog is a Gradle version catalog (e.g. `gradle/libs.versions.toml`).
+type Catalog struct {
+	// Accessor name ("libs" for `libs.versions.toml`)
+	Name string
+	// Path relative to project root
+	File string
+
+	Versions  map[string]string
+	Libraries []Library
+	Plugins   []Plugin
+	Bundles   map[string][]string
+}
+
+type Library struct {
+	Alias   string
+	Group   string
+	Name    string
+	Version string
+	// Key in [versions] table (if version is a reference)
+	VersionRef string
+	Line       int
+}
+
+func (self Library) Coordinate() string {
+	return self.Group + ":" + self.Name
+}
+
+type Plugin struct {
+	Alias      string
+	Id         string
+	Version    string
+	VersionRef string
+	Line       int
+}
+
+// NormalizeAlias converts alias to its accessor form ("androidx-core_ktx" -> "androidx.core.ktx").
+func NormalizeAlias(alias string) string {
+	return strings.TrimPrefix(report.CatalogAccessor("", alias, false), ".")
+}
+
+// Library finds library by alias or accessor path (without catalog name).
+func (self Catalog) Library(alias string) (Library, bool) {
+	alias = NormalizeAlias(alias)
+	for _, it := range self.Libraries {
+		if NormalizeAlias(it.Alias) == alias {
+			return it, true
+		}
+	}
+	return Library{}, false
+}
+
+// Bundle returns libraries of the bundle by alias or accessor path.
+func (self Catalog) Bundle(alias string) []Library {
+	alias = NormalizeAlias(alias)
+	for name, members := range self.Bundles {
+		if NormalizeAlias(name) != alias {
+			continue
+		}
+		result := []Library{}
+		for _, member := range members {
+			if lib, ok := self.Library(member); ok {
+				result = append(result, lib)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// LibraryOf finds library with coordinate ("group:name").
+func (self Catalog) LibraryOf(coordinate string) (Library, bool) {
+	for _, it := range self.Libraries {
+		if it.Coordinate() == coordinate {
+			return it, true
+		}
+	}
+	return Library{}, false
+}
+
+func Read(path string, file string) (Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Catalog{}, fmt.Errorf("could not read version catalog `%s`: %v", path, err)
+	}
+	c, err := Parse(data, file)
+	if err != nil {
+		return Catalog{}, fmt.Errorf("could not parse version catalog `%s`: %v", path, err)
+	}
+	return c, nil
+}
+
+type rawCatalog struct {
+	Versions  map[string]any      `toml:"versions"`
+	Libraries map[string]any      `toml:"libraries"`
+	Plugins   map[string]any      `toml:"plugins"`
+	Bundles   map[string][]string `toml:"bundles"`
+}
+
+// Parse parses version catalog. `file` is a path to the catalog relative to project root.
+func Parse(data []byte, file string) (Catalog, error) {
+	raw := rawCatalog{}
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return Catalog{}, err
+	}
+	lines := keyLines(string(data))
+
+	name := strings.TrimSuffix(file[strings.LastIndex(file, "/")+1:], ".versions.toml")
+	result := Catalog{
+		Name:     name,
+		File:     file,
+		Versions: map[string]string{},
+		Bundles:  raw.Bundles,
+	}
+	if result.Bundles == nil {
+		result.Bundles = map[string][]string{}
+	}
+	for key, value := range raw.Versions {
+		result.Versions[key] = versionOf(value)
+	}
+
+	for alias, value := range raw.Libraries {
+		lib := Library{Alias: alias, Line: lines["libraries."+alias]}
+		switch v := value.(type) {
+		case string:
+			// "group:name:version"
+			parts := strings.SplitN(v, ":", 3)
+			if len(parts) < 2 {
+				return Catalog{}, fmt.Errorf("library `%s`: invalid notation %q", alias, v)
+			}
+			lib.Group, lib.Name = parts[0], parts[1]
+			if len(parts) == 3 {
+				lib.Version = parts[2]
+			}
+		case map[string]any:
+			if module, ok := v["module"].(string); ok {
+				lib.Group, lib.Name, _ = strings.Cut(module, ":")
+			} else {
+				lib.Group, _ = v["group"].(string)
+				lib.Name, _ = v["name"].(string)
+			}
+			lib.Version, lib.VersionRef = result.resolveVersion(v)
+		}
+		if lib.Group == "" || lib.Name == "" {
+			return Catalog{}, fmt.Errorf("library `%s`: group and name are required", alias)
+		}
+		result.Libraries = append(result.Libraries, lib)
+	}
+
+	for alias, value := range raw.Plugins {
+		plugin := Plugin{Alias: alias, Line: lines["plugins."+alias]}
+		switch v := value.(type) {
+		case string:
+			// "id:version"
+			plugin.Id, plugin.Version, _ = strings.Cut(v, ":")
+		case map[string]any:
+			plugin.Id, _ = v["id"].(string)
+			plugin.Version, plugin.VersionRef = result.resolveVersion(v)
+		}
+		result.Plugins = append(result.Plugins, plugin)
+	}
+
+	sort.Slice(result.Libraries, func(i, j int) bool { return result.Libraries[i].Alias < result.Libraries[j].Alias })
+	sort.Slice(result.Plugins, func(i, j int) bool { return result.Plugins[i].Alias < result.Plugins[j].Alias })
+	return result, nil
+}
+
+// resolveVersion returns version of library/plugin declared as `version = "1.0"`,
+// `version.ref = "key"` or rich version.
+func (self Catalog) resolveVersion(entry map[string]any) (string, string) {
+	switch v := entry["version"].(type) {
+	case string:
+		return v, ""
+	case map[string]any:
+		if ref, ok := v["ref"].(string); ok {
+			return self.Versions[ref], ref
+		}
+		return versionOf(v), ""
+	}
+	return "", ""
+}
+
+// versionOf converts plain or rich version (`{ strictly = "1.0" }`) to string.
+func versionOf(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		for _, key := range []string{"require", "strictly", "prefer"} {
+			if s, ok := v[key].(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+var (
+	tableRegex = regexp.MustCompile(`^\s*\[\s*([\w.-]+)\s*\]`)
+	keyRegex   = regexp.MustCompile(`^\s*"?([\w.-]+?)"?\s*(\.\s*[\w.]+\s*)?=`)
+)
+
+// keyLines returns lines where keys of top-level tables are declared ("libraries.alias" -> line).
+func keyLines(data string) map[string]int {
+	result := map[string]int{}
+	table := ""
+	for i, line := range strings.Split(data, "\n") {
+		if m := tableRegex.FindStringSubmatch(line); m != nil {
+			table = m[1]
+			// Entry declared as a table (`[libraries.alias]`)
+			if _, ok := result[table]; !ok && strings.Contains(table, ".") {
+				result[table] = i + 1
+			}
+			continue
+		}
+		if table == "" {
+			continue
+		}
+		if m := keyRegex.FindStringSubmatch(line); m != nil {
+			key := table + "." + m[1]
+			if _, ok := result[key]; !ok {
+				result[key] = i + 1
+			}
+		}
+	}
+	return result
+}
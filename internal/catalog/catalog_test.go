@@ -0,0 +1,181 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lampa/internal/report"
+)
+
+const testCatalog = `[versions]
+kotlin = "2.0.0"
+okhttp = { strictly = "4.12.0" }
+
+[libraries]
+# comment
+androidx-core-ktx = { group = "androidx.core", name = "core-ktx", version = "1.13.1" }
+okhttp = { module = "com.squareup.okhttp3:okhttp", version.ref = "okhttp" }
+"okhttp-sse" = "com.squareup.okhttp3:okhttp-sse:4.12.0"
+compose_bom = { module = "androidx.compose:compose-bom", version = { require = "2024.06.00" } }
+compose-ui = { module = "androidx.compose.ui:ui" }
+
+[libraries.coil]
+module = "io.coil-kt:coil"
+version = "2.6.0"
+
+[bundles]
+network = ["okhttp", "okhttp-sse"]
+
+[plugins]
+kotlin-android = { id = "org.jetbrains.kotlin.android", version.ref = "kotlin" }
+`
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(testCatalog), "gradle/libs.versions.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "libs" {
+		t.Errorf("name: %q", c.Name)
+	}
+
+	cases := []Library{
+		{Alias: "androidx-core-ktx", Group: "androidx.core", Name: "core-ktx", Version: "1.13.1", Line: 7},
+		{Alias: "okhttp", Group: "com.squareup.okhttp3", Name: "okhttp", Version: "4.12.0", VersionRef: "okhttp", Line: 8},
+		{Alias: "okhttp-sse", Group: "com.squareup.okhttp3", Name: "okhttp-sse", Version: "4.12.0", Line: 9},
+		{Alias: "compose_bom", Group: "androidx.compose", Name: "compose-bom", Version: "2024.06.00", Line: 10},
+		{Alias: "compose-ui", Group: "androidx.compose.ui", Name: "ui", Line: 11},
+		{Alias: "coil", Group: "io.coil-kt", Name: "coil", Version: "2.6.0", Line: 13},
+	}
+	for _, want := range cases {
+		got, ok := c.Library(want.Alias)
+		if !ok || got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	}
+
+	if lib, ok := c.Library("androidx.core.ktx"); !ok || lib.Alias != "androidx-core-ktx" {
+		t.Errorf("library by accessor: %+v", lib)
+	}
+	if bundle := c.Bundle("network"); len(bundle) != 2 || bundle[1].Alias != "okhttp-sse" {
+		t.Errorf("bundle: %+v", bundle)
+	}
+	want := Plugin{Alias: "kotlin-android", Id: "org.jetbrains.kotlin.android", Version: "2.0.0", VersionRef: "kotlin", Line: 21}
+	if len(c.Plugins) != 1 || c.Plugins[0] != want {
+		t.Errorf("plugins: %+v", c.Plugins)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse([]byte("[libraries]\nx = \"nothing\"\n"), "gradle/libs.versions.toml"); err == nil {
+		t.Errorf("invalid notation accepted")
+	}
+	if _, err := Parse([]byte("[libraries\n"), "gradle/libs.versions.toml"); err == nil {
+		t.Errorf("invalid TOML accepted")
+	}
+}
+
+func TestParseBuildScript(t *testing.T) {
+	script := `plugins {
+    alias(libs.plugins.kotlin.android)
+    id("com.android.application")
+}
+
+dependencies {
+    implementation(libs.androidx.core.ktx)
+    implementation(platform(libs.compose.bom))
+    implementation(libs.bundles.network)
+    // implementation(libs.coil)
+    /*
+    implementation("com.example:commented:1.0")
+    */
+    implementation("io.coil-kt:coil:2.6.0")
+    testImplementation 'junit:junit:4.13.2@jar'
+    api(group = "org.example", name = "legacy", version = "1.0")
+    compileOnly group: 'org.example', name: 'annotations'
+    implementation(libs.versions.kotlin.get())
+    implementation(project(":core"))
+    ksp(other.room.compiler)
+    add("implementation", libs.findLibrary("compose-ui").get())
+}
+`
+	got := ParseBuildScript(script, "app/build.gradle.kts", []string{"libs"})
+	want := []Declaration{
+		{Line: 7, Configuration: "implementation", Accessor: "libs.androidx.core.ktx"},
+		{Line: 8, Configuration: "implementation", Accessor: "libs.compose.bom"},
+		{Line: 9, Configuration: "implementation", Accessor: "libs.bundles.network"},
+		{Line: 14, Configuration: "implementation", Coordinate: "io.coil-kt:coil", Version: "2.6.0"},
+		{Line: 15, Configuration: "testImplementation", Coordinate: "junit:junit", Version: "4.13.2"},
+		{Line: 16, Configuration: "api", Coordinate: "org.example:legacy", Version: "1.0"},
+		{Line: 17, Configuration: "compileOnly", Coordinate: "org.example:annotations"},
+		{Line: 21, Accessor: "libs.compose.ui"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d declarations: %+v", len(got), got)
+	}
+	for i := range want {
+		want[i].File = "app/build.gradle.kts"
+		if got[i] != want[i] {
+			t.Errorf("got %+v, want %+v", got[i], want[i])
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"gradle/libs.versions.toml": testCatalog,
+		"app/build.gradle.kts":      "dependencies {\n    implementation(libs.bundles.network)\n    implementation(\"io.coil-kt:coil:2.6.0\")\n}\n",
+		"build/app/build.gradle":    "dependencies {\n    implementation 'a:b:1.0'\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	project := Scan(dir)
+	if len(project.Catalogs) != 1 || len(project.Declarations) != 2 {
+		t.Fatalf("project: %+v", project)
+	}
+
+	cases := []struct {
+		coordinate string
+		want       report.DeclarationSegment
+		found      bool
+	}{
+		{"com.squareup.okhttp3:okhttp-sse", report.DeclarationSegment{
+			File: "app/build.gradle.kts", Line: 2, Catalog: "libs", Alias: "okhttp-sse",
+			CatalogFile: "gradle/libs.versions.toml", CatalogLine: 9,
+		}, true},
+		// Declared directly even though it's in the catalog
+		{"io.coil-kt:coil", report.DeclarationSegment{File: "app/build.gradle.kts", Line: 3}, true},
+		// Only in catalog
+		{"androidx.core:core-ktx", report.DeclarationSegment{
+			File: "gradle/libs.versions.toml", Line: 7, Catalog: "libs", Alias: "androidx-core-ktx",
+			CatalogFile: "gradle/libs.versions.toml", CatalogLine: 7,
+		}, true},
+		{"a:b", report.DeclarationSegment{}, false},
+	}
+	for _, tc := range cases {
+		group, name, _ := strings.Cut(tc.coordinate, ":")
+		got, ok := project.Declaration(group, name)
+		if ok != tc.found || got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.coordinate, got, tc.want)
+		}
+	}
+
+	r := &report.Report{}
+	r.Build.Dependencies.Compile = []report.CoordinatedDependency{{Group: "com.squareup.okhttp3", Name: "okhttp", Version: "4.12.0"}}
+	project.Annotate(r)
+	if d := r.Build.Dependencies.Compile[0].Declaration; d == nil || d.Accessor() != "libs.okhttp" {
+		t.Errorf("declaration: %+v", d)
+	}
+	if len(r.Build.Catalog) != 7 || r.Build.Catalog[6].Accessor() != "libs.plugins.kotlin.android" {
+		t.Errorf("catalog: %+v", r.Build.Catalog)
+	}
+}
@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Declaration is a dependency declared in a build script.
+type Declaration struct {
+	// Path relative to project root
+	File string
+	Line int
+
+	Configuration string
+	// Catalog accessor ("libs.androidx.core.ktx", "libs.bundles.compose")
+	// or alias from `findLibrary("...")` (prefixed with catalog name)
+	Accessor string
+	// Coordinate ("group:name") declared with string or map notation
+	Coordinate string
+	Version    string
+}
+
+var (
+	// implementation(libs.androidx.core.ktx), implementation platform(libs.compose.bom)
+	accessorRegex = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*(?:(?:enforcedP|p)latform\s*\(\s*)?([a-z]\w*(?:\.\w+)+)`)
+	// implementation("group:name:version"), implementation 'group:name'
+	stringRegex = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*(?:(?:enforcedP|p)latform\s*\(\s*)?["']([\w.-]+):([\w.-]+)(?::([^"'@:]+))?[^"']*["']`)
+	// implementation(group = "g", name = "n", version = "v"), implementation group: 'g', name: 'n'
+	mapRegex     = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*group\s*[:=]\s*["']([^"']+)["']\s*,\s*name\s*[:=]\s*["']([^"']+)["'](?:\s*,\s*version\s*[:=]\s*["']([^"']+)["'])?`)
+	findLibRegex = regexp.MustCompile(`(\w+)\.findLibrary\(\s*"([^"]+)"\s*\)`)
+)
+
+// Names that look like configurations but aren't (e.g. `id(libs.plugins.x)`).
+var notConfigurations = map[string]bool{
+	"id":    true,
+	"alias": true,
+	"val":   true,
+	"var":   true,
+	"def":   true,
+}
+
+// ParseBuildScript finds direct dependency declarations in `build.gradle(.kts)`.
+// Catalog accessors are checked against `catalogs` names to skip unrelated expressions.
+func ParseBuildScript(data string, file string, catalogs []string) []Declaration {
+	result := []Declaration{}
+	isCatalog := func(name string) bool {
+		for _, it := range catalogs {
+			if it == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	inComment := false
+	for i, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inComment {
+			if strings.Contains(trimmed, "*/") {
+				inComment = false
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "/*") {
+			inComment = !strings.Contains(trimmed, "*/")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		d := Declaration{File: file, Line: i + 1}
+		if m := mapRegex.FindStringSubmatch(line); m != nil && !notConfigurations[m[1]] {
+			d.Configuration, d.Coordinate, d.Version = m[1], m[2]+":"+m[3], m[4]
+		} else if m := stringRegex.FindStringSubmatch(line); m != nil && !notConfigurations[m[1]] {
+			d.Configuration, d.Coordinate, d.Version = m[1], m[2]+":"+m[3], m[4]
+		} else if m := accessorRegex.FindStringSubmatch(line); m != nil && !notConfigurations[m[1]] {
+			accessor := strings.TrimSuffix(m[2], ".get")
+			name, path, _ := strings.Cut(accessor, ".")
+			if !isCatalog(name) || strings.HasPrefix(path, "versions.") || strings.HasPrefix(path, "plugins.") {
+				continue
+			}
+			d.Configuration, d.Accessor = m[1], accessor
+		} else if m := findLibRegex.FindStringSubmatch(line); m != nil {
+			name := m[1]
+			if !isCatalog(name) {
+				// Convention plugins usually get catalog as `val libs = ...`
+				name = "libs"
+			}
+			d.Accessor = name + "." + NormalizeAlias(m[2])
+		} else {
+			continue
+		}
+		result = append(result, d)
+	}
+	return result
+}
@@ -0,0 +1,167 @@
+package catalog
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"lampa/internal/report"
+)
+
+// Project is a set of version catalogs and dependency declarations of the Gradle project.
+type Project struct {
+	Catalogs     []Catalog
+	Declarations []Declaration
+}
+
+var skippedDirs = map[string]bool{
+	"build":        true,
+	"node_modules": true,
+}
+
+// Scan reads version catalogs from `gradle/*.versions.toml` and dependency declarations
+// from all build scripts of the project. Unparsable files are skipped.
+func Scan(projectDir string) Project {
+	result := Project{}
+
+	catalogs, _ := filepath.Glob(filepath.Join(projectDir, "gradle", "*.versions.toml"))
+	sort.Strings(catalogs)
+	names := []string{}
+	for _, path := range catalogs {
+		c, err := Read(path, "gradle/"+filepath.Base(path))
+		if err != nil {
+			continue
+		}
+		result.Catalogs = append(result.Catalogs, c)
+		names = append(names, c.Name)
+	}
+
+	scripts := []string{}
+	_ = filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != projectDir && (skippedDirs[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if name == "build.gradle" || name == "build.gradle.kts" {
+			scripts = append(scripts, path)
+		}
+		return nil
+	})
+	sort.Strings(scripts)
+	for _, path := range scripts {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(projectDir, path)
+		if err != nil {
+			continue
+		}
+		result.Declarations = append(result.Declarations, ParseBuildScript(string(data), filepath.ToSlash(rel), names)...)
+	}
+	return result
+}
+
+// Libraries returns catalog libraries referenced by the declaration.
+func (self Project) Libraries(d Declaration) (Catalog, []Library) {
+	name, path, ok := strings.Cut(d.Accessor, ".")
+	if !ok {
+		return Catalog{}, nil
+	}
+	for _, c := range self.Catalogs {
+		if c.Name != name {
+			continue
+		}
+		if bundle, ok := strings.CutPrefix(path, "bundles."); ok {
+			return c, c.Bundle(bundle)
+		}
+		if lib, ok := c.Library(path); ok {
+			return c, []Library{lib}
+		}
+	}
+	return Catalog{}, nil
+}
+
+// Declaration finds where dependency is declared.
+// Declarations in build scripts go first, catalog entries that aren't referenced
+// directly (e.g. used by convention plugins) are returned with catalog location.
+func (self Project) Declaration(group string, name string) (report.DeclarationSegment, bool) {
+	coordinate := group + ":" + name
+	for _, d := range self.Declarations {
+		if d.Coordinate == coordinate {
+			return report.DeclarationSegment{File: d.File, Line: d.Line}, true
+		}
+		c, libs := self.Libraries(d)
+		for _, lib := range libs {
+			if lib.Coordinate() == coordinate {
+				return report.DeclarationSegment{
+					Alias:       lib.Alias,
+					Catalog:     c.Name,
+					File:        d.File,
+					Line:        d.Line,
+					CatalogFile: c.File,
+					CatalogLine: lib.Line,
+				}, true
+			}
+		}
+	}
+	for _, c := range self.Catalogs {
+		if lib, ok := c.LibraryOf(coordinate); ok {
+			return report.DeclarationSegment{
+				Alias:       lib.Alias,
+				Catalog:     c.Name,
+				File:        c.File,
+				Line:        lib.Line,
+				CatalogFile: c.File,
+				CatalogLine: lib.Line,
+			}, true
+		}
+	}
+	return report.DeclarationSegment{}, false
+}
+
+// Entries returns catalog entries for the report (libraries and plugins of all catalogs).
+func (self Project) Entries() []report.CatalogEntry {
+	result := []report.CatalogEntry{}
+	for _, c := range self.Catalogs {
+		for _, lib := range c.Libraries {
+			result = append(result, report.CatalogEntry{
+				Catalog:    c.Name,
+				Alias:      lib.Alias,
+				Module:     lib.Coordinate(),
+				Version:    lib.Version,
+				VersionRef: lib.VersionRef,
+			})
+		}
+		for _, p := range c.Plugins {
+			result = append(result, report.CatalogEntry{
+				Catalog:    c.Name,
+				Alias:      p.Alias,
+				Module:     p.Id,
+				Version:    p.Version,
+				VersionRef: p.VersionRef,
+				IsPlugin:   true,
+			})
+		}
+	}
+	return result
+}
+
+// Annotate sets declarations of report dependencies and catalog entries of the build.
+func (self Project) Annotate(r *report.Report) {
+	for i := range r.Build.Dependencies.Compile {
+		d := &r.Build.Dependencies.Compile[i]
+		if declaration, ok := self.Declaration(d.Group, d.Name); ok {
+			d.Declaration = &declaration
+		}
+	}
+	r.Build.Catalog = self.Entries()
+}
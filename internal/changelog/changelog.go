@@ -0,0 +1,141 @@
+package changelog
+
+import (
+	"fmt"
+	"lampa/internal/config"
+	"lampa/internal/gradlecache"
+	"lampa/internal/maven"
+	"strings"
+)
+
+type Link struct {
+	Title string
+	Url   string
+}
+
+// Change is a version change of the dependency.
+type Change struct {
+	Group    string
+	Artifact string
+	From     string
+	To       string
+}
+
+// Resolver builds release notes link for the version change.
+type Resolver interface {
+	Resolve(change Change) (Link, bool)
+}
+
+type ResolverFunc func(change Change) (Link, bool)
+
+func (f ResolverFunc) Resolve(change Change) (Link, bool) {
+	return f(change)
+}
+
+// Chain asks all resolvers and returns all found links.
+type Chain []Resolver
+
+func (self Chain) Resolve(change Change) []Link {
+	result := []Link{}
+	seen := map[string]bool{}
+	for _, r := range self {
+		link, ok := r.Resolve(change)
+		if ok && !seen[link.Url] {
+			seen[link.Url] = true
+			result = append(result, link)
+		}
+	}
+	return result
+}
+
+// DefaultChain returns resolvers configured by user (first) and built-in ones.
+func DefaultChain(cfg config.Config, cache gradlecache.Cache) Chain {
+	result := Chain{}
+	for _, it := range cfg.Compare.Changelogs {
+		result = append(result, TemplateResolver(it))
+	}
+	return append(result,
+		ResolverFunc(AndroidX),
+		GitHubResolver(cache),
+		ResolverFunc(MavenCentral),
+	)
+}
+
+// TemplateResolver builds link from URL template configured by user.
+func TemplateResolver(it config.ChangelogLink) Resolver {
+	return ResolverFunc(func(change Change) (Link, bool) {
+		if !it.Matches(change.Group, change.Artifact) {
+			return Link{}, false
+		}
+
+		url := strings.NewReplacer(
+			"{group}", change.Group,
+			"{artifact}", change.Artifact,
+			"{from}", change.From,
+			"{to}", change.To,
+		).Replace(it.Url)
+
+		title := it.Title
+		if title == "" {
+			title = "Release notes"
+		}
+		return Link{Title: title, Url: url}, true
+	})
+}
+
+// AndroidX links to release notes on developer.android.com.
+func AndroidX(change Change) (Link, bool) {
+	library, ok := strings.CutPrefix(change.Group, "androidx.")
+	if !ok || library == "" {
+		return Link{}, false
+	}
+	library = strings.ReplaceAll(library, ".", "-")
+
+	return Link{
+		Title: "Release notes",
+		Url:   fmt.Sprintf("https://developer.android.com/jetpack/androidx/releases/%s#%s", library, change.To),
+	}, true
+}
+
+// GitHubResolver links to GitHub comparison (or releases) page using SCM data from cached POM files.
+func GitHubResolver(cache gradlecache.Cache) Resolver {
+	return ResolverFunc(func(change Change) (Link, bool) {
+		toPom, ok := findPom(cache, change.Group, change.Artifact, change.To)
+		if !ok {
+			return Link{}, false
+		}
+		repo, ok := toPom.GitHubRepository()
+		if !ok {
+			return Link{}, false
+		}
+
+		fromPom, ok := findPom(cache, change.Group, change.Artifact, change.From)
+		if ok && fromPom.Scm.Tag != "" && toPom.Scm.Tag != "" &&
+			fromPom.Scm.Tag != "HEAD" && toPom.Scm.Tag != "HEAD" {
+			return Link{
+				Title: "Changes",
+				Url:   fmt.Sprintf("https://github.com/%s/compare/%s...%s", repo, fromPom.Scm.Tag, toPom.Scm.Tag),
+			}, true
+		}
+
+		return Link{
+			Title: "Releases",
+			Url:   fmt.Sprintf("https://github.com/%s/releases", repo),
+		}, true
+	})
+}
+
+func findPom(cache gradlecache.Cache, group string, artifact string, version string) (maven.Pom, bool) {
+	if version == "" {
+		return maven.Pom{}, false
+	}
+	return cache.ReadPom(group, artifact, version)
+}
+
+// MavenCentral links to artifact version page on Maven Central.
+func MavenCentral(change Change) (Link, bool) {
+	return Link{
+		Title: "Maven Central",
+		Url:   fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s/%s", change.Group, change.Artifact, change.To),
+	}, true
+}
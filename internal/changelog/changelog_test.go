@@ -0,0 +1,77 @@
+package changelog
+
+import (
+	"lampa/internal/config"
+	"lampa/internal/gradlecache"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writePom(t *testing.T, cache gradlecache.Cache, version string, tag string) {
+	dir := filepath.Join(cache.VersionDir("com.squareup.okhttp3", "okhttp", version), "0123abcd")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	pom := `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <groupId>com.squareup.okhttp3</groupId>
+  <artifactId>okhttp</artifactId>
+  <version>` + version + `</version>
+  <scm>
+    <url>https://github.com/square/okhttp/</url>
+    <connection>scm:git:https://github.com/square/okhttp.git</connection>
+    <tag>` + tag + `</tag>
+  </scm>
+</project>`
+	if err := os.WriteFile(filepath.Join(dir, "okhttp-"+version+".pom"), []byte(pom), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultChain(t *testing.T) {
+	cache := gradlecache.New(t.TempDir())
+	writePom(t, cache, "4.11.0", "parent-4.11.0")
+	writePom(t, cache, "4.12.0", "parent-4.12.0")
+
+	cfg := config.Config{}
+	cfg.Compare.Changelogs = []config.ChangelogLink{
+		{Match: []string{"com.example.*"}, Url: "https://example.com/{artifact}/{from}..{to}"},
+	}
+	chain := DefaultChain(cfg, cache)
+
+	cases := []struct {
+		change   Change
+		expected []Link
+	}{
+		{
+			Change{"com.squareup.okhttp3", "okhttp", "4.11.0", "4.12.0"},
+			[]Link{
+				{"Changes", "https://github.com/square/okhttp/compare/parent-4.11.0...parent-4.12.0"},
+				{"Maven Central", "https://central.sonatype.com/artifact/com.squareup.okhttp3/okhttp/4.12.0"},
+			},
+		},
+		{
+			Change{"androidx.compose.ui", "ui", "1.7.0", "1.7.4"},
+			[]Link{
+				{"Release notes", "https://developer.android.com/jetpack/androidx/releases/compose-ui#1.7.4"},
+				{"Maven Central", "https://central.sonatype.com/artifact/androidx.compose.ui/ui/1.7.4"},
+			},
+		},
+		{
+			Change{"com.example.sdk", "core", "1.0", "2.0"},
+			[]Link{
+				{"Release notes", "https://example.com/core/1.0..2.0"},
+				{"Maven Central", "https://central.sonatype.com/artifact/com.example.sdk/core/2.0"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := chain.Resolve(c.change)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Unexpected links for %v.\nGot: %v\nWant: %v", c.change, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"lampa/internal/utils"
+)
+
+// Config files that are discovered in the project root (in order of priority).
+var FileNames = []string{
+	"lampa.toml",
+	".lampa.toml",
+	"lampa.yaml",
+	"lampa.yml",
+	".lampa.yaml",
+	".lampa.yml",
+}
+
+type Config struct {
+	Collect CollectConfig `toml:"collect" yaml:"collect"`
+
+	// Dependencies excluded from reports ("group" or "group:artifact" globs).
+	Ignore []string `toml:"ignore" yaml:"ignore"`
+
+	Policy PolicyConfig `toml:"policy" yaml:"policy"`
+
+	Compare CompareConfig `toml:"compare" yaml:"compare"`
+
+	Publish PublishConfig `toml:"publish" yaml:"publish"`
+}
+
+type CollectConfig struct {
+	Variant string `toml:"variant" yaml:"variant"`
+	// Several variants (names or globs)
+	Variants        []string `toml:"variants" yaml:"variants"`
+	CombineVariants bool     `toml:"combine-variants" yaml:"combine-variants"`
+	Modules         []string `toml:"modules" yaml:"modules"`
+	Configurations  []string `toml:"configurations" yaml:"configurations"`
+	Formats         []string `toml:"formats" yaml:"formats"`
+	FileName        string   `toml:"file-name" yaml:"file-name"`
+	ToDir           string   `toml:"to-dir" yaml:"to-dir"`
+	GradleDaemon    bool     `toml:"gradle-daemon" yaml:"gradle-daemon"`
+	// Durations like "45m" (see `time.ParseDuration`)
+	BuildTimeout string `toml:"build-timeout" yaml:"build-timeout"`
+	ToolTimeout  string `toml:"tool-timeout" yaml:"tool-timeout"`
+}
+
+type PolicyConfig struct {
+	// Dependencies that must not be present in the build.
+	Deny []string `toml:"deny" yaml:"deny"`
+
+	// Fail on pre-release (alpha, beta, RC, SNAPSHOT, dev or git hash) dependencies.
+	DenyUnstable bool `toml:"deny-unstable" yaml:"deny-unstable"`
+	// Dependencies that are allowed to be unstable even if `deny-unstable` is set.
+	AllowUnstable []string `toml:"allow-unstable" yaml:"allow-unstable"`
+}
+
+type CompareConfig struct {
+	Rules []DependencyRule `toml:"rules" yaml:"rules"`
+
+	// Custom release notes links (checked before built-in ones)
+	Changelogs []ChangelogLink `toml:"changelogs" yaml:"changelogs"`
+}
+
+type PublishConfig struct {
+	// One of "github", "gitlab", "gitea" or "bitbucket"
+	Forge string `toml:"forge" yaml:"forge"`
+	// API base URL of self-hosted instance (e.g. "https://git.example.com/api/v4")
+	ApiUrl string `toml:"api-url" yaml:"api-url"`
+	// "owner/name" or GitLab project id
+	Repo string `toml:"repo" yaml:"repo"`
+}
+
+// ChangelogLink is a release notes URL template for matching dependencies.
+// Placeholders: {group}, {artifact}, {from}, {to}.
+type ChangelogLink struct {
+	Match []string `toml:"match" yaml:"match"`
+	Url   string   `toml:"url" yaml:"url"`
+	Title string   `toml:"title" yaml:"title"`
+}
+
+func (self ChangelogLink) Matches(group string, name string) bool {
+	return MatchesAnyCoordinate(self.Match, group, name)
+}
+
+const (
+	RuleActionHide     = "hide"
+	RuleActionCollapse = "collapse"
+	RuleActionRename   = "rename"
+)
+
+// DependencyRule changes how matching dependencies are displayed in comparison.
+type DependencyRule struct {
+	// "group" or "group:artifact" globs
+	Match []string `toml:"match" yaml:"match"`
+	// One of "hide", "collapse" or "rename"
+	Action string `toml:"action" yaml:"action"`
+	// Display name for renamed or collapsed dependencies
+	Name string `toml:"name" yaml:"name"`
+	// Artifact which version is shown for collapsed group (e.g. "androidx.compose:compose-bom")
+	VersionOf string `toml:"version-of" yaml:"version-of"`
+}
+
+func (self DependencyRule) Matches(group string, name string) bool {
+	return MatchesAnyCoordinate(self.Match, group, name)
+}
+
+func (self Config) Validate() error {
+	for key, value := range map[string]string{
+		"collect.build-timeout": self.Collect.BuildTimeout,
+		"collect.tool-timeout":  self.Collect.ToolTimeout,
+	} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("`%s`: %v", key, err)
+		}
+	}
+	for i, rule := range self.Compare.Rules {
+		if len(rule.Match) == 0 {
+			return fmt.Errorf("compare rule #%d: `match` is empty", i+1)
+		}
+		switch rule.Action {
+		case RuleActionHide:
+		case RuleActionCollapse, RuleActionRename:
+			if rule.Name == "" {
+				return fmt.Errorf("compare rule #%d: `name` is required for `%s` action", i+1, rule.Action)
+			}
+		default:
+			return fmt.Errorf("compare rule #%d: unknown action %q", i+1, rule.Action)
+		}
+	}
+	for i, link := range self.Compare.Changelogs {
+		if len(link.Match) == 0 || link.Url == "" {
+			return fmt.Errorf("compare changelog #%d: `match` and `url` are required", i+1)
+		}
+	}
+	return nil
+}
+
+// Find returns path to the config file in `dir` or empty string if there is none.
+func Find(dir string) string {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadFromDir discovers and loads config from `dir`.
+// Empty config is returned if no config file was found.
+func LoadFromDir(dir string) (Config, string, error) {
+	path := Find(dir)
+	if path == "" {
+		return Config{}, "", nil
+	}
+
+	cfg, err := Load(path)
+	return cfg, path, err
+}
+
+// LoadForCli loads config given by `--config` flag (`isSet`) or discovers it in the current directory.
+func LoadForCli(path string, isSet bool) (Config, error) {
+	if isSet {
+		return Load(utils.TryResolveFsPath(path))
+	}
+
+	cfg, _, err := LoadFromDir(".")
+	return cfg, err
+}
+
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("could not read config file `%s`: %v", path, err)
+	}
+
+	cfg, err := Parse(data, filepath.Ext(path))
+	if err != nil {
+		return Config{}, fmt.Errorf("could not parse config file `%s`: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes config in format defined by file extension (".toml", ".yaml" or ".yml").
+func Parse(data []byte, ext string) (Config, error) {
+	cfg := Config{}
+
+	switch strings.ToLower(ext) {
+	case ".toml":
+		meta, err := toml.Decode(string(data), &cfg)
+		if err != nil {
+			return Config{}, err
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return Config{}, fmt.Errorf("unknown key `%s`", undecoded[0])
+		}
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return Config{}, err
+		}
+	default:
+		return Config{}, fmt.Errorf("unsupported config format %q", ext)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_Toml(t *testing.T) {
+	input := `
+ignore = ["com.example.internal", "junit:junit"]
+
+[collect]
+variant = "prodRelease"
+modules = ["app", "core"]
+configurations = ["prodReleaseRuntimeClasspath"]
+formats = ["json", "html"]
+file-name = "release"
+to-dir = "build/lampa"
+
+[policy]
+deny = ["com.android.support:*"]
+`
+	result, err := Parse([]byte(input), ".toml")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	expected := Config{
+		Collect: CollectConfig{
+			Variant:        "prodRelease",
+			Modules:        []string{"app", "core"},
+			Configurations: []string{"prodReleaseRuntimeClasspath"},
+			Formats:        []string{"json", "html"},
+			FileName:       "release",
+			ToDir:          "build/lampa",
+		},
+		Ignore: []string{"com.example.internal", "junit:junit"},
+		Policy: PolicyConfig{
+			Deny: []string{"com.android.support:*"},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestParse_Yaml(t *testing.T) {
+	input := `
+collect:
+  variant: freeDebug
+  formats: [html]
+ignore:
+  - com.example.*
+`
+	result, err := Parse([]byte(input), ".yaml")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	expected := Config{
+		Collect: CollectConfig{
+			Variant: "freeDebug",
+			Formats: []string{"html"},
+		},
+		Ignore: []string{"com.example.*"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestParse_EmptyYaml(t *testing.T) {
+	result, err := Parse([]byte(""), ".yml")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if !reflect.DeepEqual(result, Config{}) {
+		t.Errorf("Expected empty config, got %+v", result)
+	}
+}
+
+func TestParse_UnknownKey(t *testing.T) {
+	if _, err := Parse([]byte("[collect]\nflavour = \"free\"\n"), ".toml"); err == nil {
+		t.Errorf("Expected error for unknown TOML key")
+	}
+	if _, err := Parse([]byte("collect:\n  flavour: free\n"), ".yaml"); err == nil {
+		t.Errorf("Expected error for unknown YAML key")
+	}
+}
+
+func TestMatchesCoordinate(t *testing.T) {
+	cases := []struct {
+		pattern string
+		group   string
+		name    string
+		matches bool
+	}{
+		{"androidx.compose.*", "androidx.compose.ui", "ui", true},
+		{"androidx.compose.*", "androidx.core", "core", false},
+		{"com.google.guava:guava", "com.google.guava", "guava", true},
+		{"com.google.guava:guava", "com.google.guava", "failureaccess", false},
+		{"*:*-android", "androidx.compose.ui", "ui-android", true},
+		{"", "androidx.core", "core", false},
+	}
+
+	for _, c := range cases {
+		if MatchesCoordinate(c.pattern, c.group, c.name) != c.matches {
+			t.Errorf("MatchesCoordinate(%q, %q, %q) expected to be %v", c.pattern, c.group, c.name, c.matches)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchesCoordinate checks if dependency matches glob pattern.
+// Pattern is either "group" or "group:artifact" (e.g. "androidx.compose.*" or "com.google.*:guava").
+func MatchesCoordinate(pattern string, group string, name string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false
+	}
+
+	subject := group
+	if strings.Contains(pattern, ":") {
+		subject = group + ":" + name
+	}
+
+	ok, err := path.Match(pattern, subject)
+	if err != nil {
+		return pattern == subject
+	}
+	return ok
+}
+
+func MatchesAnyCoordinate(patterns []string, group string, name string) bool {
+	for _, p := range patterns {
+		if MatchesCoordinate(p, group, name) {
+			return true
+		}
+	}
+	return false
+}
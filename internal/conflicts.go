@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"slices"
+)
+
+// VersionRequests holds versions requested for each artifact ("group:artifact")
+// together with their consumers.
+type VersionRequests map[string]map[string]*versionRequest
+
+type versionRequest struct {
+	consumers []string
+	// Version is requested only by dependency constraints (e.g. of platforms)
+	constraintOnly bool
+}
+
+// Add collects requested versions from the tree.
+// Direct dependencies are attributed to `rootName` (e.g. module name).
+func (self VersionRequests) Add(tree DependenciesTree, rootName string) {
+	var walk func(node Dependency, consumer string)
+	walk = func(node Dependency, consumer string) {
+		for _, child := range node.Children {
+			if child.IsAModule {
+				walk(child, "project "+child.ArtifactID)
+				continue
+			}
+
+			if child.RequestedVersion != "" {
+				self.add(child.GroupID+":"+child.ArtifactID, child.RequestedVersion, consumer, child.IsAConstraint)
+			}
+			walk(child, child.GroupID+":"+child.ArtifactID+":"+child.Version)
+		}
+	}
+	walk(tree.Root, rootName)
+}
+
+func (self VersionRequests) add(coordinate string, version string, consumer string, isConstraint bool) {
+	versions, ok := self[coordinate]
+	if !ok {
+		versions = map[string]*versionRequest{}
+		self[coordinate] = versions
+	}
+	request, ok := versions[version]
+	if !ok {
+		request = &versionRequest{constraintOnly: true}
+		versions[version] = request
+	}
+	request.constraintOnly = request.constraintOnly && isConstraint
+	if !slices.Contains(request.consumers, consumer) {
+		request.consumers = append(request.consumers, consumer)
+	}
+}
+
+// Versions returns requested versions for the artifact (sorted).
+func (self VersionRequests) Versions(coordinate string) []string {
+	result := []string{}
+	for v := range self[coordinate] {
+		result = append(result, v)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Consumers returns dependencies that requested the version (sorted).
+func (self VersionRequests) Consumers(coordinate string, version string) []string {
+	request, ok := self[coordinate][version]
+	if !ok {
+		return []string{}
+	}
+	result := slices.Clone(request.consumers)
+	slices.Sort(result)
+	return result
+}
+
+// IsConstraintOnly checks if the version is requested only by dependency constraints.
+func (self VersionRequests) IsConstraintOnly(coordinate string, version string) bool {
+	request, ok := self[coordinate][version]
+	return ok && request.constraintOnly
+}
@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"lampa/internal/report"
+)
+
+// CatalogChange is a version catalog entry that was added, removed or changed.
+type CatalogChange struct {
+	Accessor string
+	IsPlugin bool
+	// Entry before and after (nil if it was added or removed)
+	Before *report.CatalogEntry
+	After  *report.CatalogEntry
+}
+
+func (self CatalogChange) IsAdded() bool {
+	return self.Before == nil
+}
+
+func (self CatalogChange) IsRemoved() bool {
+	return self.After == nil
+}
+
+// Summary describes the change ("group:name 1.0 → 1.1").
+func (self CatalogChange) Summary() string {
+	switch {
+	case self.IsAdded():
+		return strings.TrimSpace(self.After.Module + " " + self.After.Version)
+	case self.IsRemoved():
+		return strings.TrimSpace(self.Before.Module + " " + self.Before.Version)
+	case self.Before.Module != self.After.Module:
+		return fmt.Sprintf("%s %s → %s %s", self.Before.Module, self.Before.Version, self.After.Module, self.After.Version)
+	default:
+		return fmt.Sprintf("%s %s → %s", self.After.Module, self.Before.Version, self.After.Version)
+	}
+}
+
+// FindCatalogChanges compares version catalogs of reports.
+// Reports without catalog (e.g. collected by older versions) are not compared.
+func FindCatalogChanges(r1, r2 *report.Report) []CatalogChange {
+	result := []CatalogChange{}
+	if len(r1.Build.Catalog) == 0 || len(r2.Build.Catalog) == 0 {
+		return result
+	}
+
+	before := map[string]report.CatalogEntry{}
+	for _, it := range r1.Build.Catalog {
+		before[it.Accessor()] = it
+	}
+	after := map[string]report.CatalogEntry{}
+	for _, it := range r2.Build.Catalog {
+		after[it.Accessor()] = it
+	}
+
+	for accessor, b := range before {
+		a, ok := after[accessor]
+		switch {
+		case !ok:
+			result = append(result, CatalogChange{Accessor: accessor, IsPlugin: b.IsPlugin, Before: &b})
+		case a.Module != b.Module || a.Version != b.Version:
+			result = append(result, CatalogChange{Accessor: accessor, IsPlugin: b.IsPlugin, Before: &b, After: &a})
+		}
+	}
+	for accessor, a := range after {
+		if _, ok := before[accessor]; !ok {
+			result = append(result, CatalogChange{Accessor: accessor, IsPlugin: a.IsPlugin, After: &a})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].IsPlugin != result[j].IsPlugin {
+			return !result[i].IsPlugin
+		}
+		return result[i].Accessor < result[j].Accessor
+	})
+	return result
+}
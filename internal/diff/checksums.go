@@ -0,0 +1,57 @@
+package diff
+
+import (
+	"lampa/internal/report"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// ChecksumChange is an artifact file that has different content while version is the same.
+type ChecksumChange struct {
+	Coordinate string
+	Version    string
+	File       string
+	Before     string
+	After      string
+}
+
+// FindChecksumChanges returns artifacts that kept version but changed their bytes
+// (republished artifacts, mirror mismatches or tampering).
+// Files with several (ambiguous) checksums are changed only if none of them match.
+func FindChecksumChanges(r1, r2 *report.Report) []ChecksumChange {
+	before := map[string][]string{}
+	for _, d := range r1.Build.Dependencies.Compile {
+		for _, f := range d.Files {
+			key := d.String() + "/" + f.Name
+			before[key] = append(before[key], f.Sha256)
+		}
+	}
+
+	result := []ChecksumChange{}
+	for _, d := range r2.Build.Dependencies.Compile {
+		after := map[string][]string{}
+		names := []string{}
+		for _, f := range d.Files {
+			if _, ok := after[f.Name]; !ok {
+				names = append(names, f.Name)
+			}
+			after[f.Name] = append(after[f.Name], f.Sha256)
+		}
+
+		for _, name := range names {
+			prev, ok := before[d.String()+"/"+name]
+			if !ok || lo.Some(prev, after[name]) {
+				continue
+			}
+			result = append(result, ChecksumChange{
+				Coordinate: d.Coordinate(),
+				Version:    d.Version,
+				File:       name,
+				Before:     strings.Join(prev, ", "),
+				After:      strings.Join(after[name], ", "),
+			})
+		}
+	}
+	return result
+}
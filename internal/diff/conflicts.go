@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"lampa/internal/report"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Conflict is an artifact which resolved version differs from the requested one.
+type Conflict struct {
+	Coordinate string
+	Resolved   string
+
+	// Requests with versions different from the resolved one
+	Requested []report.VersionRequest
+
+	// Resolved version has another major version than (some of) requested ones
+	IsMajor bool
+}
+
+// FindNewConflicts returns conflict resolutions that are present only in the second report.
+func FindNewConflicts(r1, r2 *report.Report) []Conflict {
+	known := map[string]bool{}
+	for _, d := range r1.Build.Dependencies.Compile {
+		for _, req := range d.ConflictingRequests() {
+			known[conflictKey(d.Coordinate(), req.Version, d.Version)] = true
+		}
+	}
+
+	result := []Conflict{}
+	for _, d := range r2.Build.Dependencies.Compile {
+		requests := lo.Filter(d.ConflictingRequests(), func(req report.VersionRequest, _ int) bool {
+			return !known[conflictKey(d.Coordinate(), req.Version, d.Version)]
+		})
+		if len(requests) == 0 {
+			continue
+		}
+
+		result = append(result, Conflict{
+			Coordinate: d.Coordinate(),
+			Resolved:   d.Version,
+			Requested:  requests,
+			IsMajor: lo.ContainsBy(requests, func(req report.VersionRequest) bool {
+				return IsMajorChange(req.Version, d.Version)
+			}),
+		})
+	}
+
+	return result
+}
+
+func conflictKey(coordinate string, requested string, resolved string) string {
+	return coordinate + ":" + requested + "->" + resolved
+}
+
+// IsMajorChange checks if major component differs between versions.
+func IsMajorChange(v1 string, v2 string) bool {
+	m1, ok1 := majorVersion(v1)
+	m2, ok2 := majorVersion(v2)
+	return ok1 && ok2 && m1 != m2
+}
+
+func majorVersion(v string) (int, bool) {
+	end := strings.IndexFunc(v, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if end == -1 {
+		end = len(v)
+	}
+	major, err := strconv.Atoi(v[:end])
+	return major, err == nil
+}
@@ -0,0 +1,305 @@
+package diff
+
+import (
+	"fmt"
+	"lampa/internal/changelog"
+	"lampa/internal/config"
+	"lampa/internal/report"
+	"lampa/internal/versions"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+type Dep struct {
+	Coordinate string
+	Version    string
+
+	// Platform/BOM that defined the version
+	ManagedBy  string
+	IsPlatform bool
+
+	// Version catalog accessor ("libs.androidx.core.ktx") of direct dependency
+	Alias string
+
+	// Display name (when renamed or collapsed by rules)
+	Label string
+	// Dependencies that are collapsed into this one
+	Members []Dep
+
+	// Release notes for the version change
+	Links []changelog.Link
+}
+
+type DependenciesDiff struct {
+	New        []Dep
+	Removed    []Dep
+	Upgraded   []Dep
+	Downgraded []Dep
+	// Versions that can't be ordered (e.g. commit hashes)
+	Changed   []Dep
+	Unchanged []Dep
+}
+
+// Comparison is a difference between two reports.
+type Comparison struct {
+	Mode Mode
+
+	Dependencies DependenciesDiff
+
+	// Newly introduced version conflict resolutions
+	Conflicts []Conflict
+
+	// Newly introduced pre-release dependencies
+	Unstable []Dep
+
+	// Artifacts with the same version but different content
+	ChecksumChanges []ChecksumChange
+
+	// Dependencies resolved from a different repository and repositories that weren't used before
+	RepositoryChanges []RepositoryChange
+	NewRepositories   []NewRepository
+
+	// Manifest permissions
+	Permissions PermissionsDiff
+
+	// Changed version catalog entries
+	Catalog []CatalogChange
+}
+
+func Compare(r1, r2 *report.Report, cfg config.Config) Comparison {
+	return Comparison{
+		Mode:         DetectMode(r1, r2),
+		Dependencies: CompareDependencies(r1, r2, cfg.Compare.Rules),
+		Conflicts:    FindNewConflicts(r1, r2),
+		Unstable:     FindNewUnstableDeps(r1, r2),
+
+		ChecksumChanges:   FindChecksumChanges(r1, r2),
+		RepositoryChanges: FindRepositoryChanges(r1, r2),
+		NewRepositories:   FindNewRepositories(r1, r2),
+		Permissions:       FindPermissionChanges(r1, r2),
+		Catalog:           FindCatalogChanges(r1, r2),
+	}
+}
+
+// CompareDependencies finds changes in compile dependencies between two reports.
+func CompareDependencies(r1, r2 *report.Report, rules []config.DependencyRule) DependenciesDiff {
+	d1 := lo.Map(r1.Build.Dependencies.Compile, func(d report.CoordinatedDependency, _ int) Dep {
+		return FromReport(d)
+	})
+	d2 := lo.Map(r2.Build.Dependencies.Compile, func(d report.CoordinatedDependency, _ int) Dep {
+		return FromReport(d)
+	})
+
+	result := DependenciesDiff{
+		New:        findNewDeps(d1, d2),
+		Removed:    findRemovedDeps(d1, d2),
+		Upgraded:   findUpgradedDeps(d1, d2),
+		Downgraded: findDowngradedDeps(d1, d2),
+		Changed:    findChangedDeps(d1, d2),
+		Unchanged:  findUnchangedDeps(d1, d2),
+	}
+	result.New = groupByPlatforms(result.New)
+	result.Removed = groupByPlatforms(result.Removed)
+	result.Upgraded = groupByPlatforms(result.Upgraded)
+	result.Downgraded = groupByPlatforms(result.Downgraded)
+	result.Changed = groupByPlatforms(result.Changed)
+	result = applyRules(result, rules, d1, d2)
+
+	for _, deps := range result.Categories() {
+		sortDeps(deps)
+	}
+
+	return result
+}
+
+// Categories returns all dependency lists (in display order).
+func (self DependenciesDiff) Categories() [][]Dep {
+	return [][]Dep{self.New, self.Removed, self.Upgraded, self.Downgraded, self.Changed, self.Unchanged}
+}
+
+func (self *DependenciesDiff) mapCategories(f func([]Dep) []Dep) {
+	self.New = f(self.New)
+	self.Removed = f(self.Removed)
+	self.Upgraded = f(self.Upgraded)
+	self.Downgraded = f(self.Downgraded)
+	self.Changed = f(self.Changed)
+	self.Unchanged = f(self.Unchanged)
+}
+
+func sortDeps(deps []Dep) {
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Name() < deps[j].Name()
+	})
+}
+
+func (d Dep) EqCoord(other Dep) bool {
+	return d.Coordinate == other.Coordinate
+}
+
+// Name returns label if it's set or coordinate otherwise.
+func (d Dep) Name() string {
+	if d.Label != "" {
+		return d.Label
+	}
+	return d.Coordinate
+}
+
+func (d Dep) GroupAndArtifact() (string, string) {
+	parts := strings.SplitN(d.Coordinate, ":", 2)
+	if len(parts) != 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+func (d Dep) String() string {
+	return fmt.Sprintf("%s:%s", d.Coordinate, d.Version)
+}
+
+// VersionRange returns previous and new versions (previous one is empty if version is not changed).
+func (d Dep) VersionRange() (string, string) {
+	parts := strings.SplitN(d.Version, "→", 2)
+	if len(parts) == 2 {
+		return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	}
+	return "", d.Version
+}
+
+// Stability of the (new) version.
+func (d Dep) Stability() versions.Stability {
+	_, version := d.VersionRange()
+	return versions.Classify(version)
+}
+
+func (d Dep) IsComparable(other Dep) bool {
+	return versions.IsComparable(d.Version, other.Version)
+}
+
+func (d Dep) IsLater(other Dep) bool {
+	return versions.IsLater(d.Version, other.Version)
+}
+
+func FromReport(d report.CoordinatedDependency) Dep {
+	result := Dep{
+		Coordinate: d.Coordinate(),
+		Version:    d.Version,
+		ManagedBy:  d.ManagedBy,
+		IsPlatform: d.IsPlatform,
+	}
+	if d.Declaration != nil {
+		result.Alias = d.Declaration.Accessor()
+	}
+	return result
+}
+
+func ParseDep(s string) Dep {
+	parts := strings.Split(s, ":")
+	return Dep{
+		Coordinate: parts[0] + ":" + parts[1],
+		Version:    parts[2],
+	}
+}
+
+func findNewDeps(d1, d2 []Dep) []Dep {
+	depsNew := make([]Dep, 0, len(d2))
+	for _, d := range d2 {
+		_, ok := lo.Find(d1, func(it Dep) bool {
+			return d.EqCoord(it)
+		})
+		if !ok {
+			depsNew = append(depsNew, d)
+		}
+	}
+	return depsNew
+}
+
+func findRemovedDeps(d1, d2 []Dep) []Dep {
+	depsRemoved := make([]Dep, 0, len(d1))
+	for _, d := range d1 {
+		_, ok := lo.Find(d2, func(it Dep) bool {
+			return d.EqCoord(it)
+		})
+		if !ok {
+			depsRemoved = append(depsRemoved, d)
+		}
+	}
+	return depsRemoved
+}
+
+func findUpgradedDeps(d1, d2 []Dep) []Dep {
+	depsUpgraded := make([]Dep, 0, len(d2))
+	for _, d := range d2 {
+		it, ok := lo.Find(d1, func(it Dep) bool {
+			return d.EqCoord(it)
+		})
+		if ok && d.IsComparable(it) {
+			if d.IsLater(it) {
+				depsUpgraded = append(depsUpgraded, Dep{
+					Coordinate: d.Coordinate,
+					Version:    fmt.Sprintf("%s → %s", it.Version, d.Version),
+					ManagedBy:  d.ManagedBy,
+					IsPlatform: d.IsPlatform,
+					Alias:      d.Alias,
+				})
+			}
+		}
+	}
+	return depsUpgraded
+}
+
+func findDowngradedDeps(d1, d2 []Dep) []Dep {
+	depsDowngraded := make([]Dep, 0, len(d1))
+	for _, d := range d1 {
+		it, ok := lo.Find(d2, func(it Dep) bool {
+			return d.EqCoord(it)
+		})
+		if ok && d.IsComparable(it) {
+			if d.IsLater(it) {
+				depsDowngraded = append(depsDowngraded, Dep{
+					Coordinate: it.Coordinate,
+					Version:    fmt.Sprintf("%s → %s", d.Version, it.Version),
+					ManagedBy:  it.ManagedBy,
+					IsPlatform: it.IsPlatform,
+					Alias:      it.Alias,
+				})
+			}
+		}
+	}
+	return depsDowngraded
+}
+
+func findChangedDeps(d1, d2 []Dep) []Dep {
+	depsChanged := make([]Dep, 0, len(d2))
+	for _, d := range d2 {
+		it, ok := lo.Find(d1, func(it Dep) bool {
+			return d.EqCoord(it)
+		})
+		if ok && d.Version != it.Version && !d.IsComparable(it) {
+			depsChanged = append(depsChanged, Dep{
+				Coordinate: d.Coordinate,
+				Version:    fmt.Sprintf("%s → %s", it.Version, d.Version),
+				ManagedBy:  d.ManagedBy,
+				IsPlatform: d.IsPlatform,
+				Alias:      d.Alias,
+			})
+		}
+	}
+	return depsChanged
+}
+
+func findUnchangedDeps(d1, d2 []Dep) []Dep {
+	depsUnchanged := make([]Dep, 0, len(d1))
+	for _, d := range d1 {
+		it, ok := lo.Find(d2, func(it Dep) bool {
+			return d.EqCoord(it)
+		})
+		if ok {
+			if d.Version == it.Version {
+				depsUnchanged = append(depsUnchanged, d)
+			}
+		}
+	}
+	return depsUnchanged
+}
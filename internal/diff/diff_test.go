@@ -0,0 +1,390 @@
+package diff
+
+import (
+	"lampa/internal/config"
+	"lampa/internal/report"
+	"reflect"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+func TestCompareDependencies_GroupByPlatform(t *testing.T) {
+	r1 := reportWith(
+		"com.google.firebase:firebase-bom:33.6.0",
+		"com.google.firebase:firebase-analytics:22.1.0",
+		"com.google.firebase:firebase-common:21.0.0",
+	)
+	r2 := reportWith(
+		"com.google.firebase:firebase-bom:33.7.0",
+		"com.google.firebase:firebase-analytics:22.1.2",
+		"com.google.firebase:firebase-common:21.0.0",
+	)
+	r2.Build.Dependencies.Compile[0].IsPlatform = true
+	r2.Build.Dependencies.Compile[1].ManagedBy = "com.google.firebase:firebase-bom"
+	r2.Build.Dependencies.Compile[2].ManagedBy = "com.google.firebase:firebase-bom"
+
+	result := CompareDependencies(r1, r2, nil)
+
+	if len(result.Upgraded) != 1 {
+		t.Fatalf("Expected 1 upgraded entry, got: %v", result.Upgraded)
+	}
+	bom := result.Upgraded[0]
+	if bom.Coordinate != "com.google.firebase:firebase-bom" || bom.Version != "33.6.0 → 33.7.0" {
+		t.Errorf("Expected BOM upgrade, got: %v", bom)
+	}
+	if len(bom.Members) != 1 || bom.Members[0].Coordinate != "com.google.firebase:firebase-analytics" {
+		t.Errorf("Expected analytics upgrade to be grouped under BOM, got: %v", bom.Members)
+	}
+	if len(result.Unchanged) != 1 {
+		t.Errorf("Expected unchanged dependencies to stay ungrouped, got: %v", result.Unchanged)
+	}
+}
+
+func TestFindNewConflicts(t *testing.T) {
+	r1 := reportWith(
+		"jakarta.inject:jakarta.inject-api:2.0.2",
+		"com.squareup.okio:okio:3.9.0",
+	)
+	r1.Build.Dependencies.Compile[0].Requested = []report.VersionRequest{
+		{Version: "2.0.1", RequestedBy: []string{"com.google.dagger:dagger:2.56"}},
+	}
+
+	r2 := reportWith(
+		"jakarta.inject:jakarta.inject-api:2.0.2",
+		"com.squareup.okio:okio:3.9.0",
+	)
+	r2.Build.Dependencies.Compile[0].Requested = []report.VersionRequest{
+		{Version: "2.0.1", RequestedBy: []string{"com.google.dagger:dagger:2.57"}},
+	}
+	r2.Build.Dependencies.Compile[1].Requested = []report.VersionRequest{
+		{Version: "2.10.0", RequestedBy: []string{"com.squareup.retrofit2:retrofit:2.11.0"}},
+		{Version: "3.9.0", RequestedBy: []string{"app"}},
+	}
+
+	result := FindNewConflicts(r1, r2)
+
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 new conflict, got: %v", result)
+	}
+	if result[0].Coordinate != "com.squareup.okio:okio" || result[0].Resolved != "3.9.0" {
+		t.Errorf("Unexpected conflict: %v", result[0])
+	}
+	if len(result[0].Requested) != 1 || result[0].Requested[0].Version != "2.10.0" {
+		t.Errorf("Expected only conflicting request, got: %v", result[0].Requested)
+	}
+	if !result[0].IsMajor {
+		t.Errorf("Expected conflict to be a major version change")
+	}
+}
+
+func TestFindNewConflicts_SatisfiedRequests(t *testing.T) {
+	r1 := reportWith()
+	r2 := reportWith(
+		"com.squareup.okhttp3:okhttp:4.12.0",
+		"androidx.core:core:1.13.0",
+	)
+	r2.Build.Dependencies.Compile[0].Requested = []report.VersionRequest{
+		{Version: "4.+", RequestedBy: []string{"app"}},
+		{Version: "[4.0,5.0)", RequestedBy: []string{"com.example:a:1.0"}},
+		{Version: "latest.release", RequestedBy: []string{"com.example:b:1.0"}},
+		{Version: "[3.0,4.0)", RequestedBy: []string{"com.example:c:1.0"}},
+	}
+	r2.Build.Dependencies.Compile[1].Requested = []report.VersionRequest{
+		{Version: "1.12.0", RequestedBy: []string{"androidx.compose:compose-bom:2024.10.00"}, Constraint: true},
+	}
+
+	result := FindNewConflicts(r1, r2)
+
+	if len(result) != 1 || result[0].Coordinate != "com.squareup.okhttp3:okhttp" {
+		t.Fatalf("Expected only okhttp conflict, got: %v", result)
+	}
+	if len(result[0].Requested) != 1 || result[0].Requested[0].Version != "[3.0,4.0)" {
+		t.Errorf("Expected only unsatisfied range to conflict, got: %v", result[0].Requested)
+	}
+}
+
+func TestCompareDependencies_NonSemverVersions(t *testing.T) {
+	r1 := reportWith(
+		"com.google.guava:guava:33.1.0-android",
+		"androidx.compose.material3:material3:1.4.0-alpha05",
+		"com.github.example:lib:3a6b2fe",
+	)
+	r2 := reportWith(
+		"com.google.guava:guava:33.2.0-android",
+		"androidx.compose.material3:material3:1.4.0-beta01",
+		"com.github.example:lib:9c1d2ab",
+	)
+
+	result := CompareDependencies(r1, r2, nil)
+
+	if len(result.New) != 0 {
+		t.Errorf("Expected no new dependencies, got: %v", result.New)
+	}
+	if len(result.Upgraded) != 2 {
+		t.Errorf("Expected 2 upgraded dependencies, got: %v", result.Upgraded)
+	}
+	if len(result.Changed) != 1 || result.Changed[0].Version != "3a6b2fe → 9c1d2ab" {
+		t.Errorf("Expected 1 uncomparable change, got: %v", result.Changed)
+	}
+}
+
+func TestFindNewUnstableDeps(t *testing.T) {
+	r1 := reportWith(
+		"androidx.core:core:1.15.0",
+		"androidx.compose.material3:material3:1.4.0-alpha05",
+	)
+	r2 := reportWith(
+		"androidx.core:core:1.16.0-beta01",
+		"androidx.compose.material3:material3:1.4.0-alpha08",
+		"com.example:lib:1.0.0-SNAPSHOT",
+	)
+
+	result := FindNewUnstableDeps(r1, r2)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 introduced unstable dependencies, got: %v", result)
+	}
+	if result[0].Coordinate != "androidx.core:core" || result[0].Version != "1.15.0 → 1.16.0-beta01" {
+		t.Errorf("Unexpected dependency: %v", result[0])
+	}
+	if result[1].Coordinate != "com.example:lib" {
+		t.Errorf("Unexpected dependency: %v", result[1])
+	}
+}
+
+func TestFindChecksumChanges(t *testing.T) {
+	r1 := reportWith("com.example:lib:1.0.0", "com.example:other:1.0.0")
+	r1.Build.Dependencies.Compile[0].Files = []report.ArtifactFile{{Name: "lib-1.0.0.jar", Sha256: "aaa"}}
+	r1.Build.Dependencies.Compile[1].Files = []report.ArtifactFile{{Name: "other-1.0.0.jar", Sha256: "ccc"}}
+
+	r2 := reportWith("com.example:lib:1.0.0", "com.example:other:1.0.1")
+	r2.Build.Dependencies.Compile[0].Files = []report.ArtifactFile{{Name: "lib-1.0.0.jar", Sha256: "bbb"}}
+	r2.Build.Dependencies.Compile[1].Files = []report.ArtifactFile{{Name: "other-1.0.1.jar", Sha256: "ddd"}}
+
+	changes := FindChecksumChanges(r1, r2)
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 checksum change, got: %#v", changes)
+	}
+	c := changes[0]
+	if c.Coordinate != "com.example:lib" || c.File != "lib-1.0.0.jar" || c.Before != "aaa" || c.After != "bbb" {
+		t.Errorf("Unexpected checksum change: %#v", c)
+	}
+}
+
+func TestFindChecksumChangesAmbiguous(t *testing.T) {
+	r1 := reportWith("com.example:lib:1.0.0", "com.example:other:1.0.0")
+	r1.Build.Dependencies.Compile[0].Files = []report.ArtifactFile{{Name: "lib-1.0.0.jar", Sha256: "aaa"}}
+	r1.Build.Dependencies.Compile[1].Files = []report.ArtifactFile{{Name: "other-1.0.0.jar", Sha256: "ccc"}}
+
+	r2 := reportWith("com.example:lib:1.0.0", "com.example:other:1.0.0")
+	// Republished copy is in cache next to the original one
+	r2.Build.Dependencies.Compile[0].Files = []report.ArtifactFile{
+		{Name: "lib-1.0.0.jar", Sha256: "aaa", Ambiguous: true},
+		{Name: "lib-1.0.0.jar", Sha256: "bbb", Ambiguous: true},
+	}
+	r2.Build.Dependencies.Compile[1].Files = []report.ArtifactFile{
+		{Name: "other-1.0.0.jar", Sha256: "ddd", Ambiguous: true},
+		{Name: "other-1.0.0.jar", Sha256: "eee", Ambiguous: true},
+	}
+
+	changes := FindChecksumChanges(r1, r2)
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 checksum change, got: %#v", changes)
+	}
+	if c := changes[0]; c.Coordinate != "com.example:other" || c.Before != "ccc" || c.After != "ddd, eee" {
+		t.Errorf("Unexpected checksum change: %#v", c)
+	}
+}
+
+func TestFindRepositoryChanges(t *testing.T) {
+	central := &report.RepositorySegment{Name: "Maven Central", Url: "https://repo.maven.apache.org/maven2/"}
+	nexus := &report.RepositorySegment{Name: "Nexus", Url: "https://nexus.example.com/maven/"}
+
+	r1 := reportWith("com.example:lib:1.0.0", "com.example:other:1.0.0")
+	r1.Build.Dependencies.Compile[0].Repository = central
+	r1.Build.Dependencies.Compile[1].Repository = central
+
+	r2 := reportWith("com.example:lib:1.0.1", "com.example:other:1.0.0", "com.example:new:1.0.0")
+	r2.Build.Dependencies.Compile[0].Repository = nexus
+	r2.Build.Dependencies.Compile[1].Repository = central
+	r2.Build.Dependencies.Compile[2].Repository = nexus
+
+	changes := FindRepositoryChanges(r1, r2)
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 repository change, got: %#v", changes)
+	}
+	if changes[0].Coordinate != "com.example:lib" || changes[0].Version != "1.0.0 → 1.0.1" || changes[0].After.Name != "Nexus" {
+		t.Errorf("Unexpected repository change: %#v", changes[0])
+	}
+
+	added := FindNewRepositories(r1, r2)
+	if len(added) != 1 || added[0].Repository.Name != "Nexus" || len(added[0].Dependencies) != 2 {
+		t.Errorf("Unexpected new repositories: %#v", added)
+	}
+
+	if added := FindNewRepositories(reportWith("com.example:lib:1.0.0"), r2); len(added) != 0 {
+		t.Errorf("Expected no new repositories when first report has no repository data, got: %#v", added)
+	}
+}
+
+func TestDetectMode(t *testing.T) {
+	reportOf := func(commit string, variant string) *report.Report {
+		r := &report.Report{}
+		r.Context.Git.Commit = commit
+		r.Build.BuildVariant = variant
+		return r
+	}
+
+	cases := []struct {
+		r1, r2   *report.Report
+		expected Mode
+	}{
+		{reportOf("abc", "freeRelease"), reportOf("abc", "paidRelease"), ModeVariants},
+		{reportOf("abc", "release"), reportOf("def", "release"), ModeReleases},
+		{reportOf("abc", "freeRelease"), reportOf("def", "paidRelease"), ModeReleases},
+		{reportOf("abc", "release"), reportOf("abc", "release"), ModeReleases},
+		{reportOf("", "freeRelease"), reportOf("", "paidRelease"), ModeReleases},
+	}
+	for i, c := range cases {
+		if actual := DetectMode(c.r1, c.r2); actual != c.expected {
+			t.Errorf("case #%d: expected %q, got %q", i+1, c.expected, actual)
+		}
+	}
+}
+
+func TestCompareMatrix(t *testing.T) {
+	release := reportWith("a:x:1.0", "a:y:1.0", "a:z:1.0")
+	hotfix := reportWith("a:x:1.1", "a:y:1.0", "a:z:1.0", "a:w:1.0")
+	main := reportWith("a:x:1.0", "a:y:1.0", "a:w:1.0")
+	for i, r := range []*report.Report{release, hotfix, main} {
+		r.Build.VersionName = "1.0"
+		r.Build.VersionCode = "1"
+		r.Context.Git.Commit = []string{"1111111aaa", "2222222bbb", "3333333ccc"}[i]
+	}
+
+	result := CompareMatrix([]*report.Report{release, hotfix, main}, []config.DependencyRule{
+		{Match: []string{"a:y"}, Action: config.RuleActionHide},
+	})
+
+	expectedColumns := []string{"1.0 (1) @ 1111111", "1.0 (1) @ 2222222", "1.0 (1) @ 3333333"}
+	if !reflect.DeepEqual(result.Columns, expectedColumns) {
+		t.Errorf("unexpected columns %v", result.Columns)
+	}
+
+	actual := map[string][]CellChange{}
+	for _, row := range result.Rows {
+		actual[row.Coordinate] = lo.Map(row.Cells, func(c MatrixCell, _ int) CellChange { return c.Change })
+	}
+	expected := map[string][]CellChange{
+		"a:w": {CellAbsent, CellNew, CellUnchanged},
+		"a:x": {CellBase, CellUpgraded, CellDowngraded},
+		"a:z": {CellBase, CellUnchanged, CellRemoved},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected cells %v", actual)
+	}
+	if result.ChangedRows() != 3 {
+		t.Errorf("expected 3 changed rows, got %d", result.ChangedRows())
+	}
+}
+
+func TestFindPermissionChanges(t *testing.T) {
+	r1 := reportWith()
+	r1.Build.Permissions = []string{"android.permission.CAMERA", "android.permission.INTERNET"}
+	r2 := reportWith()
+	r2.Build.Permissions = []string{"android.permission.INTERNET", "android.permission.READ_CONTACTS"}
+
+	result := FindPermissionChanges(r1, r2)
+
+	expected := PermissionsDiff{
+		Added:   []string{"android.permission.READ_CONTACTS"},
+		Removed: []string{"android.permission.CAMERA"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected permission changes %v", result)
+	}
+
+	// Old reports have no permissions
+	r1.Build.Permissions = nil
+	if result := FindPermissionChanges(r1, r2); !result.IsEmpty() {
+		t.Errorf("report without permissions compared: %v", result)
+	}
+}
+
+func TestFindPermissionChanges_FromNone(t *testing.T) {
+	r1 := reportWith()
+	r1.Build.PermissionsCollected = true
+	r2 := reportWith()
+	r2.Build.Permissions = []string{"android.permission.INTERNET"}
+	r2.Build.PermissionsCollected = true
+
+	result := FindPermissionChanges(r1, r2)
+
+	expected := PermissionsDiff{Added: []string{"android.permission.INTERNET"}, Removed: []string{}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected permission changes %v", result)
+	}
+
+	// Back to no permissions
+	result = FindPermissionChanges(r2, r1)
+	expected = PermissionsDiff{Added: []string{}, Removed: []string{"android.permission.INTERNET"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected permission changes %v", result)
+	}
+}
+
+func TestFindCatalogChanges(t *testing.T) {
+	r1 := reportWith()
+	r1.Build.Catalog = []report.CatalogEntry{
+		{Catalog: "libs", Alias: "okhttp", Module: "com.squareup.okhttp3:okhttp", Version: "4.11.0"},
+		{Catalog: "libs", Alias: "androidx-core-ktx", Module: "androidx.core:core-ktx", Version: "1.13.1"},
+		{Catalog: "libs", Alias: "coil", Module: "io.coil-kt:coil", Version: "2.6.0"},
+		{Catalog: "libs", Alias: "agp", Module: "com.android.application", Version: "8.5.0", IsPlugin: true},
+	}
+	r2 := reportWith()
+	r2.Build.Catalog = []report.CatalogEntry{
+		{Catalog: "libs", Alias: "okhttp", Module: "com.squareup.okhttp3:okhttp", Version: "4.12.0"},
+		{Catalog: "libs", Alias: "androidx_core_ktx", Module: "androidx.core:core-ktx", Version: "1.13.1"},
+		{Catalog: "libs", Alias: "coil3", Module: "io.coil-kt.coil3:coil", Version: "3.0.0"},
+		{Catalog: "libs", Alias: "agp", Module: "com.android.application", Version: "8.6.0", IsPlugin: true},
+	}
+
+	got := []string{}
+	for _, c := range FindCatalogChanges(r1, r2) {
+		switch {
+		case c.IsAdded():
+			got = append(got, "+"+c.Accessor+" "+c.After.Version)
+		case c.IsRemoved():
+			got = append(got, "-"+c.Accessor+" "+c.Before.Version)
+		default:
+			got = append(got, "~"+c.Accessor+" "+c.Before.Version+" → "+c.After.Version)
+		}
+	}
+	expected := []string{
+		"-libs.coil 2.6.0",
+		"+libs.coil3 3.0.0",
+		"~libs.okhttp 4.11.0 → 4.12.0",
+		"~libs.plugins.agp 8.5.0 → 8.6.0",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected catalog changes %v", got)
+	}
+
+	// Old reports have no catalog
+	r1.Build.Catalog = nil
+	if changes := FindCatalogChanges(r1, r2); len(changes) != 0 {
+		t.Errorf("report without catalog compared: %v", changes)
+	}
+}
+
+func TestFromReportAlias(t *testing.T) {
+	d := report.CoordinatedDependency{Group: "a", Name: "b", Version: "1.0"}
+	if dep := FromReport(d); dep.Alias != "" {
+		t.Errorf("unexpected alias %q", dep.Alias)
+	}
+	d.Declaration = &report.DeclarationSegment{File: "app/build.gradle.kts", Line: 3, Catalog: "libs", Alias: "a-b"}
+	if dep := FromReport(d); dep.Alias != "libs.a.b" {
+		t.Errorf("unexpected alias %q", dep.Alias)
+	}
+}
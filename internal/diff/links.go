@@ -0,0 +1,30 @@
+package diff
+
+import "lampa/internal/changelog"
+
+// AddChangelogLinks resolves release notes links for changed dependencies.
+func (self *DependenciesDiff) AddChangelogLinks(resolver changelog.Chain) {
+	var add func(deps []Dep)
+	add = func(deps []Dep) {
+		for i := range deps {
+			d := &deps[i]
+			add(d.Members)
+
+			from, to := d.VersionRange()
+			group, artifact := d.GroupAndArtifact()
+			if from == "" || artifact == "" {
+				continue
+			}
+			d.Links = resolver.Resolve(changelog.Change{
+				Group:    group,
+				Artifact: artifact,
+				From:     from,
+				To:       to,
+			})
+		}
+	}
+
+	add(self.Upgraded)
+	add(self.Downgraded)
+	add(self.Changed)
+}
@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"fmt"
+	"lampa/internal/config"
+	"lampa/internal/report"
+	"lampa/internal/versions"
+	"slices"
+	"sort"
+
+	"github.com/samber/lo"
+)
+
+// CellChange is a change of dependency version relative to the previous report.
+type CellChange string
+
+const (
+	// Dependency is present in the first report
+	CellBase       CellChange = "base"
+	CellAbsent     CellChange = "absent"
+	CellNew        CellChange = "new"
+	CellRemoved    CellChange = "removed"
+	CellUpgraded   CellChange = "upgraded"
+	CellDowngraded CellChange = "downgraded"
+	CellChanged    CellChange = "changed"
+	CellUnchanged  CellChange = "unchanged"
+)
+
+type MatrixCell struct {
+	// Empty if dependency is absent
+	Version string
+	Change  CellChange
+}
+
+type MatrixRow struct {
+	Coordinate string
+	// Display name (when renamed by rules)
+	Label string
+	Cells []MatrixCell
+}
+
+// Matrix is a comparison of several reports: dependencies are rows and reports are columns.
+type Matrix struct {
+	Columns []string
+	Rows    []MatrixRow
+}
+
+func (self MatrixRow) Name() string {
+	if self.Label != "" {
+		return self.Label
+	}
+	return self.Coordinate
+}
+
+// HasChanges reports whether version differs between any of the reports.
+func (self MatrixRow) HasChanges() bool {
+	return lo.SomeBy(self.Cells, func(c MatrixCell) bool {
+		return c.Change != CellBase && c.Change != CellUnchanged && c.Change != CellAbsent
+	})
+}
+
+// ChangedRows returns number of rows with changes.
+func (self Matrix) ChangedRows() int {
+	return lo.CountBy(self.Rows, MatrixRow.HasChanges)
+}
+
+// CompareMatrix compares compile dependencies of reports, every report is compared with the previous one.
+// Only "hide" and "rename" rules are applied.
+func CompareMatrix(reports []*report.Report, rules []config.DependencyRule) Matrix {
+	result := Matrix{Columns: MatrixColumns(reports)}
+
+	versionsOf := map[string][]string{}
+	for i, r := range reports {
+		for _, d := range r.Build.Dependencies.Compile {
+			coordinate := d.Coordinate()
+			if _, ok := versionsOf[coordinate]; !ok {
+				versionsOf[coordinate] = make([]string, len(reports))
+			}
+			versionsOf[coordinate][i] = d.Version
+		}
+	}
+
+	for coordinate, versions := range versionsOf {
+		row := MatrixRow{Coordinate: coordinate}
+
+		hidden := false
+		for _, rule := range rules {
+			if !matches(rule, Dep{Coordinate: coordinate}) {
+				continue
+			}
+			switch rule.Action {
+			case config.RuleActionHide:
+				hidden = true
+			case config.RuleActionRename:
+				row.Label = rule.Name
+			}
+		}
+		if hidden {
+			continue
+		}
+
+		for i, version := range versions {
+			previous := ""
+			if i > 0 {
+				previous = versions[i-1]
+			}
+			row.Cells = append(row.Cells, MatrixCell{
+				Version: version,
+				Change:  cellChange(i == 0, previous, version),
+			})
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	sort.Slice(result.Rows, func(i, j int) bool {
+		return result.Rows[i].Name() < result.Rows[j].Name()
+	})
+	return result
+}
+
+func cellChange(isFirst bool, previous string, current string) CellChange {
+	switch {
+	case current == "" && (isFirst || previous == ""):
+		return CellAbsent
+	case isFirst:
+		return CellBase
+	case previous == "":
+		return CellNew
+	case current == "":
+		return CellRemoved
+	case previous == current:
+		return CellUnchanged
+	case !versions.IsComparable(previous, current):
+		return CellChanged
+	case versions.IsLater(current, previous):
+		return CellUpgraded
+	default:
+		return CellDowngraded
+	}
+}
+
+// MatrixColumns returns column names: build variants if all reports are of the same commit, versions otherwise.
+// Ambiguous names are extended with commit.
+func MatrixColumns(reports []*report.Report) []string {
+	sameCommit := len(reports) > 0 && reports[0].Context.Git.Commit != "" && lo.EveryBy(reports, func(r *report.Report) bool {
+		return r.Context.Git.Commit == reports[0].Context.Git.Commit
+	})
+
+	result := lo.Map(reports, func(r *report.Report, _ int) string {
+		if sameCommit {
+			return r.Build.BuildVariant
+		}
+		return fmt.Sprintf("%s (%s)", r.Build.VersionName, r.Build.VersionCode)
+	})
+	counts := lo.CountValues(result)
+	for i, r := range reports {
+		if counts[result[i]] > 1 {
+			commit := r.Context.Git.Commit
+			if len(commit) > 7 {
+				commit = commit[:7]
+			}
+			result[i] = fmt.Sprintf("%s @ %s", result[i], commit)
+		}
+	}
+	if !slices.Equal(result, lo.Uniq(result)) {
+		for i := range result {
+			result[i] = fmt.Sprintf("#%d %s", i+1, result[i])
+		}
+	}
+	return result
+}
@@ -0,0 +1,24 @@
+package diff
+
+import "lampa/internal/report"
+
+// Mode defines what is compared and how comparison is presented.
+type Mode string
+
+const (
+	// Two releases (or any two builds) of the app
+	ModeReleases Mode = "releases"
+	// Different build variants (flavors) of the same commit
+	ModeVariants Mode = "variants"
+)
+
+// DetectMode returns ModeVariants if reports were collected from the same commit for different build variants.
+func DetectMode(r1, r2 *report.Report) Mode {
+	if r1.Context.Git.Commit == "" || r1.Context.Git.Commit != r2.Context.Git.Commit {
+		return ModeReleases
+	}
+	if r1.Build.BuildVariant == r2.Build.BuildVariant {
+		return ModeReleases
+	}
+	return ModeVariants
+}
@@ -0,0 +1,36 @@
+package diff
+
+import (
+	"slices"
+
+	"lampa/internal/report"
+)
+
+type PermissionsDiff struct {
+	Added   []string
+	Removed []string
+}
+
+func (self PermissionsDiff) IsEmpty() bool {
+	return len(self.Added) == 0 && len(self.Removed) == 0
+}
+
+// FindPermissionChanges finds manifest permissions that were added or removed.
+// Reports without collected permissions (e.g. collected by older versions) are not compared.
+func FindPermissionChanges(r1, r2 *report.Report) PermissionsDiff {
+	result := PermissionsDiff{Added: []string{}, Removed: []string{}}
+	if !r1.Build.HasPermissions() || !r2.Build.HasPermissions() {
+		return result
+	}
+	for _, p := range r2.Build.Permissions {
+		if !slices.Contains(r1.Build.Permissions, p) {
+			result.Added = append(result.Added, p)
+		}
+	}
+	for _, p := range r1.Build.Permissions {
+		if !slices.Contains(r2.Build.Permissions, p) {
+			result.Removed = append(result.Removed, p)
+		}
+	}
+	return result
+}
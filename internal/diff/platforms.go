@@ -0,0 +1,34 @@
+package diff
+
+// groupByPlatforms moves dependencies under the platform/BOM that defined their version
+// (if the platform itself is in the same list).
+func groupByPlatforms(deps []Dep) []Dep {
+	platforms := map[string]bool{}
+	for _, d := range deps {
+		if d.IsPlatform {
+			platforms[d.Coordinate] = true
+		}
+	}
+	if len(platforms) == 0 {
+		return deps
+	}
+
+	result := make([]Dep, 0, len(deps))
+	members := map[string][]Dep{}
+	for _, d := range deps {
+		if d.ManagedBy != "" && platforms[d.ManagedBy] {
+			members[d.ManagedBy] = append(members[d.ManagedBy], d)
+			continue
+		}
+		result = append(result, d)
+	}
+
+	for i := range result {
+		if result[i].IsPlatform {
+			result[i].Members = members[result[i].Coordinate]
+			sortDeps(result[i].Members)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"slices"
+
+	"lampa/internal/report"
+)
+
+// RepositoryChange is a dependency that is resolved from a different repository.
+type RepositoryChange struct {
+	Coordinate string
+	// Version (or "a → b" if it was changed too)
+	Version string
+	Before  report.RepositorySegment
+	After   report.RepositorySegment
+}
+
+// NewRepository is a repository that didn't provide any dependency before.
+type NewRepository struct {
+	Repository   report.RepositorySegment
+	Dependencies []string
+}
+
+// FindRepositoryChanges returns dependencies that moved between repositories.
+// Dependencies without known repository in any of the reports are skipped.
+func FindRepositoryChanges(r1, r2 *report.Report) []RepositoryChange {
+	before := map[string]report.CoordinatedDependency{}
+	for _, d := range r1.Build.Dependencies.Compile {
+		if d.Repository != nil {
+			before[d.Coordinate()] = d
+		}
+	}
+
+	result := []RepositoryChange{}
+	for _, d := range r2.Build.Dependencies.Compile {
+		prev, ok := before[d.Coordinate()]
+		if !ok || d.Repository == nil || prev.Repository.Key() == d.Repository.Key() {
+			continue
+		}
+
+		version := d.Version
+		if prev.Version != d.Version {
+			version = prev.Version + " → " + d.Version
+		}
+		result = append(result, RepositoryChange{
+			Coordinate: d.Coordinate(),
+			Version:    version,
+			Before:     *prev.Repository,
+			After:      *d.Repository,
+		})
+	}
+	return result
+}
+
+// FindNewRepositories returns repositories that appear only in the second report.
+// Nothing is returned if the first report has no repository information.
+func FindNewRepositories(r1, r2 *report.Report) []NewRepository {
+	known := map[string]bool{}
+	for _, d := range r1.Build.Dependencies.Compile {
+		if d.Repository != nil {
+			known[d.Repository.Key()] = true
+		}
+	}
+	if len(known) == 0 {
+		return []NewRepository{}
+	}
+
+	result := []NewRepository{}
+	for _, d := range r2.Build.Dependencies.Compile {
+		if d.Repository == nil || known[d.Repository.Key()] {
+			continue
+		}
+
+		idx := slices.IndexFunc(result, func(r NewRepository) bool {
+			return r.Repository.Key() == d.Repository.Key()
+		})
+		if idx < 0 {
+			result = append(result, NewRepository{Repository: *d.Repository})
+			idx = len(result) - 1
+		}
+		result[idx].Dependencies = append(result[idx].Dependencies, d.String())
+	}
+	return result
+}
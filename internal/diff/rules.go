@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"fmt"
+	"lampa/internal/config"
+
+	"github.com/samber/lo"
+)
+
+func applyRules(result DependenciesDiff, rules []config.DependencyRule, d1, d2 []Dep) DependenciesDiff {
+	for _, rule := range rules {
+		switch rule.Action {
+		case config.RuleActionHide:
+			result.mapCategories(func(deps []Dep) []Dep {
+				return lo.Reject(deps, func(d Dep, _ int) bool {
+					return matches(rule, d)
+				})
+			})
+		case config.RuleActionRename:
+			result.mapCategories(func(deps []Dep) []Dep {
+				return lo.Map(deps, func(d Dep, _ int) Dep {
+					if matches(rule, d) {
+						d.Label = rule.Name
+					}
+					return d
+				})
+			})
+		case config.RuleActionCollapse:
+			result.New = collapse(result.New, rule, d1, d2, sideAfter)
+			result.Removed = collapse(result.Removed, rule, d1, d2, sideBefore)
+			result.Upgraded = collapse(result.Upgraded, rule, d1, d2, sideBoth)
+			result.Downgraded = collapse(result.Downgraded, rule, d1, d2, sideBoth)
+			result.Changed = collapse(result.Changed, rule, d1, d2, sideBoth)
+			result.Unchanged = collapse(result.Unchanged, rule, d1, d2, sideAfter)
+		}
+	}
+	return result
+}
+
+func matches(rule config.DependencyRule, d Dep) bool {
+	// Already collapsed by another rule
+	if d.Label != "" && len(d.Members) > 0 {
+		return false
+	}
+	group, artifact := d.GroupAndArtifact()
+	return rule.Matches(group, artifact)
+}
+
+// versionSide defines which version of `version-of` artifact is shown for collapsed category.
+type versionSide int
+
+const (
+	// "prev → next" (if version was changed)
+	sideBoth versionSide = iota
+	sideBefore
+	sideAfter
+)
+
+func collapse(deps []Dep, rule config.DependencyRule, d1, d2 []Dep, side versionSide) []Dep {
+	members, rest := lo.FilterReject(deps, func(d Dep, _ int) bool {
+		return matches(rule, d)
+	})
+	if len(members) == 0 {
+		return deps
+	}
+
+	group := Dep{
+		Coordinate: rule.VersionOf,
+		Label:      rule.Name,
+		Members:    members,
+	}
+
+	if rule.VersionOf != "" {
+		prev, hasPrev := lo.Find(d1, func(it Dep) bool { return it.Coordinate == rule.VersionOf })
+		next, hasNext := lo.Find(d2, func(it Dep) bool { return it.Coordinate == rule.VersionOf })
+		switch {
+		case side == sideBoth && hasPrev && hasNext && prev.Version != next.Version:
+			group.Version = fmt.Sprintf("%s → %s", prev.Version, next.Version)
+		case side == sideBefore && hasPrev:
+			group.Version = prev.Version
+		case side != sideBefore && hasNext:
+			group.Version = next.Version
+		case hasPrev:
+			group.Version = prev.Version
+		}
+	}
+	if group.Version == "" {
+		versions := lo.Uniq(lo.Map(members, func(d Dep, _ int) string { return d.Version }))
+		if len(versions) == 1 {
+			group.Version = versions[0]
+		}
+	}
+
+	return append(rest, group)
+}
@@ -0,0 +1,133 @@
+package diff
+
+import (
+	"lampa/internal/config"
+	"lampa/internal/report"
+	"testing"
+)
+
+func reportWith(deps ...string) *report.Report {
+	r := &report.Report{}
+	for _, d := range deps {
+		dep := ParseDep(d)
+		group, name := dep.GroupAndArtifact()
+		r.Build.Dependencies.Compile = append(r.Build.Dependencies.Compile, report.CoordinatedDependency{
+			Group:   group,
+			Name:    name,
+			Version: dep.Version,
+		})
+	}
+	return r
+}
+
+func TestCompareDependencies_CollapseRule(t *testing.T) {
+	r1 := reportWith(
+		"androidx.compose:compose-bom:2024.09.00",
+		"androidx.compose.ui:ui:1.7.0",
+		"androidx.compose.foundation:foundation:1.7.0",
+		"com.squareup.okhttp3:okhttp:4.12.0",
+	)
+	r2 := reportWith(
+		"androidx.compose:compose-bom:2024.10.00",
+		"androidx.compose.ui:ui:1.7.4",
+		"androidx.compose.foundation:foundation:1.7.4",
+		"com.squareup.okhttp3:okhttp:4.12.0",
+	)
+	rules := []config.DependencyRule{
+		{
+			Match:     []string{"androidx.compose.*", "androidx.compose"},
+			Action:    config.RuleActionCollapse,
+			Name:      "Compose BOM",
+			VersionOf: "androidx.compose:compose-bom",
+		},
+	}
+
+	result := CompareDependencies(r1, r2, rules)
+
+	if len(result.Upgraded) != 1 {
+		t.Fatalf("Expected 1 upgraded entry, got: %v", result.Upgraded)
+	}
+	group := result.Upgraded[0]
+	if group.Name() != "Compose BOM" {
+		t.Errorf("Expected collapsed entry name, got: %s", group.Name())
+	}
+	if group.Version != "2024.09.00 → 2024.10.00" {
+		t.Errorf("Expected BOM version change, got: %s", group.Version)
+	}
+	if len(group.Members) != 3 {
+		t.Errorf("Expected 3 members, got: %v", group.Members)
+	}
+	if len(result.Unchanged) != 1 || result.Unchanged[0].Coordinate != "com.squareup.okhttp3:okhttp" {
+		t.Errorf("Expected okhttp to be unchanged, got: %v", result.Unchanged)
+	}
+}
+
+func TestCompareDependencies_CollapseUnchangedGroup(t *testing.T) {
+	r1 := reportWith(
+		"androidx.compose:compose-bom:2024.09.00",
+		"androidx.compose.ui:ui:1.7.0",
+		"androidx.compose.runtime:runtime:1.7.0",
+		"androidx.compose.material3:material3:1.3.0",
+	)
+	r2 := reportWith(
+		"androidx.compose:compose-bom:2024.10.00",
+		"androidx.compose.ui:ui:1.7.4",
+		"androidx.compose.runtime:runtime:1.7.0",
+		"androidx.compose.animation:animation:1.7.4",
+	)
+	rules := []config.DependencyRule{
+		{
+			Match:     []string{"androidx.compose.*", "androidx.compose"},
+			Action:    config.RuleActionCollapse,
+			Name:      "Compose BOM",
+			VersionOf: "androidx.compose:compose-bom",
+		},
+	}
+
+	result := CompareDependencies(r1, r2, rules)
+
+	for name, c := range map[string]struct {
+		deps    []Dep
+		version string
+	}{
+		"upgraded":  {result.Upgraded, "2024.09.00 → 2024.10.00"},
+		"unchanged": {result.Unchanged, "2024.10.00"},
+		"new":       {result.New, "2024.10.00"},
+		"removed":   {result.Removed, "2024.09.00"},
+	} {
+		if len(c.deps) != 1 || c.deps[0].Name() != "Compose BOM" {
+			t.Errorf("Expected collapsed %s entry, got: %v", name, c.deps)
+			continue
+		}
+		if c.deps[0].Version != c.version {
+			t.Errorf("Expected %s BOM version %s, got: %s", name, c.version, c.deps[0].Version)
+		}
+	}
+}
+
+func TestCompareDependencies_HideAndRenameRules(t *testing.T) {
+	r1 := reportWith(
+		"junit:junit:4.12",
+		"com.google.guava:guava:32.0.0",
+	)
+	r2 := reportWith(
+		"junit:junit:4.13.2",
+		"com.google.guava:guava:33.0.0",
+	)
+	rules := []config.DependencyRule{
+		{Match: []string{"junit"}, Action: config.RuleActionHide},
+		{Match: []string{"com.google.guava:guava"}, Action: config.RuleActionRename, Name: "Guava"},
+	}
+
+	result := CompareDependencies(r1, r2, rules)
+
+	if len(result.Upgraded) != 1 {
+		t.Fatalf("Expected 1 upgraded entry, got: %v", result.Upgraded)
+	}
+	if result.Upgraded[0].Name() != "Guava" {
+		t.Errorf("Expected renamed entry, got: %s", result.Upgraded[0].Name())
+	}
+	if result.Upgraded[0].Coordinate != "com.google.guava:guava" {
+		t.Errorf("Expected coordinate to be kept, got: %s", result.Upgraded[0].Coordinate)
+	}
+}
@@ -0,0 +1,31 @@
+package diff
+
+import (
+	"fmt"
+	"lampa/internal/report"
+
+	"github.com/samber/lo"
+)
+
+// FindNewUnstableDeps returns pre-release dependencies from the second report
+// that are new or were stable in the first one.
+func FindNewUnstableDeps(r1, r2 *report.Report) []Dep {
+	result := []Dep{}
+	for _, d := range r2.Build.Dependencies.Compile {
+		if d.Stability().IsStable() {
+			continue
+		}
+
+		prev, ok := lo.Find(r1.Build.Dependencies.Compile, func(it report.CoordinatedDependency) bool {
+			return it.Coordinate() == d.Coordinate()
+		})
+		if !ok {
+			result = append(result, FromReport(d))
+		} else if prev.Stability().IsStable() {
+			dep := FromReport(d)
+			dep.Version = fmt.Sprintf("%s → %s", prev.Version, d.Version)
+			result = append(result, dep)
+		}
+	}
+	return result
+}
@@ -0,0 +1,220 @@
+package findings
+
+import (
+	"fmt"
+	"lampa/internal/diff"
+	"lampa/internal/policy"
+	"lampa/internal/report"
+	"strings"
+)
+
+// Level of the finding (same as SARIF levels).
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+type Rule struct {
+	Id          string
+	Name        string
+	Description string
+	Level       Level
+}
+
+var (
+	RuleNewDependency = Rule{
+		Id: "new-dependency", Name: "New dependencies",
+		Description: "Dependency was added to the build.", Level: LevelNote,
+	}
+	RuleDowngradedDependency = Rule{
+		Id: "downgraded-dependency", Name: "Downgraded dependencies",
+		Description: "Dependency version is older than in the previous report.", Level: LevelWarning,
+	}
+	RuleUnstableDependency = Rule{
+		Id: "unstable-dependency", Name: "New unstable dependencies",
+		Description: "Pre-release (alpha, beta, RC, SNAPSHOT) dependency was introduced.", Level: LevelWarning,
+	}
+	RuleNewPermission = Rule{
+		Id: "new-permission", Name: "New permissions",
+		Description: "Application requests a new permission.", Level: LevelWarning,
+	}
+	RuleChecksumChange = Rule{
+		Id: "checksum-change", Name: "Checksum changes",
+		Description: "Artifact kept its version but its content changed.", Level: LevelError,
+	}
+	RuleRepositoryChange = Rule{
+		Id: "repository-change", Name: "Repository changes",
+		Description: "Dependency is resolved from another repository.", Level: LevelWarning,
+	}
+	RuleVersionConflict = Rule{
+		Id: "version-conflict", Name: "Version conflicts",
+		Description: "Gradle resolved a new version conflict.", Level: LevelNote,
+	}
+	RulePolicyDeny = Rule{
+		Id: "policy/deny", Name: "Denied dependencies",
+		Description: "Dependency is denied by `policy.deny`.", Level: LevelError,
+	}
+	RulePolicyDenyUnstable = Rule{
+		Id: "policy/deny-unstable", Name: "Unstable dependencies",
+		Description: "Dependency is not stable while `policy.deny-unstable` is set.", Level: LevelError,
+	}
+)
+
+// Rules checked by comparison.
+var CompareRules = []Rule{
+	RuleNewDependency,
+	RuleDowngradedDependency,
+	RuleUnstableDependency,
+	RuleNewPermission,
+	RuleChecksumChange,
+	RuleRepositoryChange,
+	RuleVersionConflict,
+}
+
+// Rules checked by policy.
+var PolicyRules = []Rule{
+	RulePolicyDeny,
+	RulePolicyDenyUnstable,
+}
+
+type Finding struct {
+	Rule    Rule
+	Message string
+
+	// "group:artifact" of the dependency (empty if finding is not about dependency)
+	Coordinate string
+	Version    string
+	// Manifest permission
+	Permission string
+
+	// Where the finding is declared (empty if unknown)
+	Location Location
+}
+
+// Location in the project (path is relative to the project directory).
+type Location struct {
+	File string
+	Line int
+}
+
+func (self Location) IsEmpty() bool {
+	return self.File == ""
+}
+
+func (self Location) String() string {
+	if self.Line > 0 {
+		return fmt.Sprintf("%s:%d", self.File, self.Line)
+	}
+	return self.File
+}
+
+// Subject is a short name of the finding (used as test case name).
+func (self Finding) Subject() string {
+	switch {
+	case self.Permission != "":
+		return self.Permission
+	case self.Version != "":
+		return self.Coordinate + ":" + self.Version
+	default:
+		return self.Coordinate
+	}
+}
+
+// FromComparison converts comparison to findings.
+func FromComparison(c diff.Comparison) []Finding {
+	result := []Finding{}
+	for _, d := range c.Dependencies.New {
+		result = append(result, depFinding(RuleNewDependency, d, "%s %s was added"))
+	}
+	for _, d := range c.Dependencies.Downgraded {
+		result = append(result, depFinding(RuleDowngradedDependency, d, "%s was downgraded (%s)"))
+	}
+	for _, d := range c.Unstable {
+		result = append(result, depFinding(RuleUnstableDependency, d, "%s %s is not stable"))
+	}
+	for _, p := range c.Permissions.Added {
+		result = append(result, Finding{
+			Rule:       RuleNewPermission,
+			Message:    fmt.Sprintf("Permission %s was added", p),
+			Permission: p,
+		})
+	}
+	for _, it := range c.ChecksumChanges {
+		result = append(result, Finding{
+			Rule:       RuleChecksumChange,
+			Message:    fmt.Sprintf("%s changed without version change (%s → %s)", it.File, short(it.Before), short(it.After)),
+			Coordinate: it.Coordinate,
+			Version:    it.Version,
+		})
+	}
+	for _, it := range c.RepositoryChanges {
+		result = append(result, Finding{
+			Rule:       RuleRepositoryChange,
+			Message:    fmt.Sprintf("%s moved from %s to %s", it.Coordinate, repositoryName(it.Before), repositoryName(it.After)),
+			Coordinate: it.Coordinate,
+			Version:    it.Version,
+		})
+	}
+	for _, it := range c.Conflicts {
+		requested := []string{}
+		for _, r := range it.Requested {
+			requested = append(requested, r.Version)
+		}
+		result = append(result, Finding{
+			Rule:       RuleVersionConflict,
+			Message:    fmt.Sprintf("%s resolved to %s (requested %s)", it.Coordinate, it.Resolved, strings.Join(requested, ", ")),
+			Coordinate: it.Coordinate,
+			Version:    it.Resolved,
+		})
+	}
+	return result
+}
+
+// FromViolations converts policy violations to findings.
+func FromViolations(violations []policy.Violation) []Finding {
+	result := []Finding{}
+	for _, v := range violations {
+		rule := RulePolicyDeny
+		if v.Rule == "deny-unstable" {
+			rule = RulePolicyDenyUnstable
+		}
+		result = append(result, Finding{
+			Rule:       rule,
+			Message:    v.Message,
+			Coordinate: fmt.Sprintf("%s:%s", v.Dependency.Group, v.Dependency.Name),
+			Version:    v.Dependency.Version,
+		})
+	}
+	return result
+}
+
+func depFinding(rule Rule, d diff.Dep, format string) Finding {
+	_, version := d.VersionRange()
+	return Finding{
+		Rule:       rule,
+		Message:    fmt.Sprintf(format, d.Name(), d.Version),
+		Coordinate: d.Coordinate,
+		Version:    version,
+	}
+}
+
+func repositoryName(r report.RepositorySegment) string {
+	if r.Url != "" {
+		return r.Url
+	}
+	return r.Name
+}
+
+// short shortens checksum (or comma-separated list of ambiguous ones).
+func short(checksums string) string {
+	parts := strings.Split(checksums, ", ")
+	for i, it := range parts {
+		if len(it) > 12 {
+			parts[i] = it[:12]
+		}
+	}
+	return strings.Join(parts, ", ")
+}
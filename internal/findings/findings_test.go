@@ -0,0 +1,195 @@
+package findings
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lampa/internal/diff"
+	"lampa/internal/policy"
+	"lampa/internal/report"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLocator(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"gradle/libs.versions.toml": `[versions]
+okhttp = "4.12.0"
+
+[libraries]
+# "com.example:commented:1.0"
+okhttp = { module = "com.squareup.okhttp3:okhttp", version.ref = "okhttp" }
+coil = { group = "io.coil-kt", name = "coil", version = "2.6.0" }
+`,
+		"app/build.gradle.kts": `plugins {
+    alias(libs.plugins.android.application)
+}
+dependencies {
+    implementation(libs.okhttp)
+    implementation("com.squareup.okhttp3:okhttp-sse:4.12.0")
+    implementation(group = "org.example", name = "legacy", version = "1.0")
+}
+`,
+		"lib/build.gradle": `dependencies {
+    implementation 'com.squareup.okhttp3:logging-interceptor:4.12.0'
+}
+`,
+		"app/src/main/AndroidManifest.xml": `<manifest>
+    <uses-permission
+        android:name="android.permission.CAMERA" />
+</manifest>
+`,
+		"app/build/intermediates/AndroidManifest.xml": `<uses-permission android:name="android.permission.INTERNET" />`,
+	})
+	locator := NewLocator(dir)
+
+	cases := []struct {
+		finding Finding
+		want    Location
+	}{
+		{Finding{Coordinate: "com.squareup.okhttp3:okhttp"}, Location{"gradle/libs.versions.toml", 6}},
+		{Finding{Coordinate: "io.coil-kt:coil"}, Location{"gradle/libs.versions.toml", 7}},
+		{Finding{Coordinate: "com.squareup.okhttp3:okhttp-sse"}, Location{"app/build.gradle.kts", 6}},
+		{Finding{Coordinate: "org.example:legacy"}, Location{"app/build.gradle.kts", 7}},
+		{Finding{Coordinate: "com.squareup.okhttp3:logging-interceptor"}, Location{"lib/build.gradle", 2}},
+		{Finding{Permission: "android.permission.CAMERA"}, Location{"app/src/main/AndroidManifest.xml", 3}},
+		{Finding{Permission: "android.permission.INTERNET"}, Location{}},
+		{Finding{Coordinate: "com.example:commented"}, Location{}},
+		{Finding{Coordinate: "com.squareup.okhttp3:okhttp3"}, Location{}},
+	}
+	for _, tc := range cases {
+		got, _ := locator.Locate(tc.finding)
+		if got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.finding.Subject(), got, tc.want)
+		}
+	}
+	if fallback := locator.Fallback(); fallback != (Location{File: "app/build.gradle.kts"}) {
+		t.Errorf("fallback: %v", fallback)
+	}
+}
+
+func TestFromComparison(t *testing.T) {
+	c := diff.Comparison{
+		Dependencies: diff.DependenciesDiff{
+			New:        []diff.Dep{{Coordinate: "a:new", Version: "1.0"}},
+			Downgraded: []diff.Dep{{Coordinate: "a:old", Version: "2.0 → 1.0"}},
+			Upgraded:   []diff.Dep{{Coordinate: "a:up", Version: "1.0 → 2.0"}},
+		},
+		Permissions: diff.PermissionsDiff{Added: []string{"android.permission.CAMERA"}, Removed: []string{"x"}},
+	}
+
+	got := []string{}
+	for _, f := range FromComparison(c) {
+		got = append(got, f.Rule.Id+" "+f.Subject())
+	}
+	want := []string{
+		"new-dependency a:new:1.0",
+		"downgraded-dependency a:old:1.0",
+		"new-permission android.permission.CAMERA",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFromViolations(t *testing.T) {
+	violations := []policy.Violation{
+		{Rule: "deny", Dependency: report.CoordinatedDependency{Group: "a", Name: "b", Version: "1"}, Message: "denied"},
+		{Rule: "deny-unstable", Dependency: report.CoordinatedDependency{Group: "a", Name: "c", Version: "1-beta"}, Message: "unstable"},
+	}
+	got := FromViolations(violations)
+	if got[0].Rule.Id != RulePolicyDeny.Id || got[0].Subject() != "a:b:1" {
+		t.Errorf("got %+v", got[0])
+	}
+	if got[1].Rule.Id != RulePolicyDenyUnstable.Id || got[1].Subject() != "a:c:1-beta" {
+		t.Errorf("got %+v", got[1])
+	}
+}
+
+func testFindings() []Finding {
+	return []Finding{
+		{Rule: RuleNewDependency, Message: "a:new 1.0 was added", Coordinate: "a:new", Version: "1.0"},
+		{
+			Rule: RuleDowngradedDependency, Message: "a:old was downgraded (2.0 → 1.0)", Coordinate: "a:old", Version: "1.0",
+			Location: Location{"gradle/libs.versions.toml", 3},
+		},
+	}
+}
+
+func TestWriteJunit(t *testing.T) {
+	b := &bytes.Buffer{}
+	rules := []Rule{RuleNewDependency, RuleDowngradedDependency, RuleNewPermission}
+	if err := WriteJunit(b, "lampa compare", rules, testFindings()); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := junitTestSuites{}
+	if err := xml.Unmarshal(b.Bytes(), &parsed); err != nil {
+		t.Fatalf("%v\n%s", err, b)
+	}
+	if parsed.Tests != 3 || parsed.Failures != 1 || len(parsed.Suites) != 3 {
+		t.Errorf("tests %d, failures %d, suites %d", parsed.Tests, parsed.Failures, len(parsed.Suites))
+	}
+
+	downgraded := parsed.Suites[1].TestCases[0]
+	if downgraded.Name != "a:old:1.0" || downgraded.Failure == nil || downgraded.File != "gradle/libs.versions.toml" {
+		t.Errorf("downgraded: %+v", downgraded)
+	}
+	if added := parsed.Suites[0].TestCases[0]; added.Failure != nil || added.SystemOut == "" {
+		t.Errorf("new: %+v", added)
+	}
+	if passed := parsed.Suites[2].TestCases[0]; passed.Name != RuleNewPermission.Name || passed.Failure != nil {
+		t.Errorf("rule without findings: %+v", passed)
+	}
+}
+
+func TestWriteSarif(t *testing.T) {
+	b := &bytes.Buffer{}
+	rules := []Rule{RuleNewDependency, RuleDowngradedDependency}
+	if err := WriteSarif(b, rules, testFindings(), Location{File: "app/build.gradle.kts"}); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := sarifLog{}
+	if err := json.Unmarshal(b.Bytes(), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Version != "2.1.0" || len(parsed.Runs) != 1 {
+		t.Fatalf("log: %+v", parsed)
+	}
+	run := parsed.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("run: %+v", run)
+	}
+	if r := run.Results[0]; r.Level != "note" || len(r.Locations) != 1 ||
+		r.Locations[0].PhysicalLocation.ArtifactLocation.Uri != "app/build.gradle.kts" ||
+		r.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("result without location: %+v", r)
+	}
+	r := run.Results[1]
+	if r.RuleId != "downgraded-dependency" || r.RuleIndex != 1 || r.Level != "warning" {
+		t.Errorf("result: %+v", r)
+	}
+	location := r.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.Uri != "gradle/libs.versions.toml" || location.Region.StartLine != 3 {
+		t.Errorf("location: %+v", location)
+	}
+}
@@ -0,0 +1,87 @@
+package findings
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJunit writes findings as JUnit XML: a test suite per rule, a test case per finding
+// (failed unless it's a note). Rules without findings are written as a single passed test case.
+func WriteJunit(w io.Writer, name string, rules []Rule, findings []Finding) error {
+	suites := junitTestSuites{Name: name}
+	for _, rule := range rules {
+		suite := junitTestSuite{Name: "lampa." + rule.Id}
+		for _, f := range findings {
+			if f.Rule.Id != rule.Id {
+				continue
+			}
+			tc := junitTestCase{
+				ClassName: suite.Name,
+				Name:      f.Subject(),
+				File:      f.Location.File,
+				Line:      f.Location.Line,
+			}
+			if f.Rule.Level == LevelNote {
+				tc.SystemOut = f.Message
+			} else {
+				text := f.Message
+				if !f.Location.IsEmpty() {
+					text += "\n" + f.Location.String()
+				}
+				tc.Failure = &junitFailure{Message: f.Message, Type: string(f.Rule.Level), Text: text}
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		if len(suite.TestCases) == 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{ClassName: suite.Name, Name: rule.Name})
+		}
+		suite.Tests = len(suite.TestCases)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return fmt.Errorf("could not write JUnit report: %v", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
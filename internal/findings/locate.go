@@ -0,0 +1,140 @@
+package findings
+
+import (
+	"io/fs"
+	"lampa/internal/catalog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type sourceFile struct {
+	Path  string
+	Lines []string
+}
+
+// Locator finds where dependencies and permissions are declared in the project
+// (version catalogs, build scripts and manifests).
+type Locator struct {
+	project   catalog.Project
+	manifests []sourceFile
+	// Build script of the application module (if found)
+	appScript string
+}
+
+var skippedDirs = map[string]bool{
+	"build":        true,
+	"node_modules": true,
+}
+
+// NewLocator reads source files of the project.
+func NewLocator(projectDir string) *Locator {
+	manifests := []sourceFile{}
+	appScripts := []string{}
+	_ = filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != projectDir && (skippedDirs[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		isScript := name == "build.gradle" || name == "build.gradle.kts"
+		if name != "AndroidManifest.xml" && !isScript {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(projectDir, path)
+		if err != nil {
+			return nil
+		}
+		if isScript {
+			if isAppScript(string(data)) {
+				appScripts = append(appScripts, filepath.ToSlash(rel))
+			}
+			return nil
+		}
+		manifests = append(manifests, sourceFile{
+			Path:  filepath.ToSlash(rel),
+			Lines: strings.Split(string(data), "\n"),
+		})
+		return nil
+	})
+
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].Path < manifests[j].Path })
+	sort.Strings(appScripts)
+	result := &Locator{project: catalog.Scan(projectDir), manifests: manifests}
+	if len(appScripts) > 0 {
+		result.appScript = appScripts[0]
+	}
+	return result
+}
+
+func isAppScript(content string) bool {
+	return strings.Contains(content, "com.android.application") ||
+		strings.Contains(content, "plugins.android.application")
+}
+
+// Fallback returns file-level location for findings that can't be located:
+// build script of the application module or its manifest.
+func (self *Locator) Fallback() Location {
+	if self.appScript != "" {
+		return Location{File: self.appScript}
+	}
+	for _, file := range self.manifests {
+		if strings.HasSuffix(file.Path, "src/main/AndroidManifest.xml") {
+			return Location{File: file.Path}
+		}
+	}
+	if len(self.manifests) > 0 {
+		return Location{File: self.manifests[0].Path}
+	}
+	return Location{}
+}
+
+// Annotate sets locations of findings that can be found.
+func (self *Locator) Annotate(findings []Finding) {
+	for i := range findings {
+		if location, ok := self.Locate(findings[i]); ok {
+			findings[i].Location = location
+		}
+	}
+}
+
+// Locate returns location of the dependency or permission of the finding.
+// Catalog entry is preferred for dependencies as their versions are declared there.
+func (self *Locator) Locate(f Finding) (Location, bool) {
+	switch {
+	case f.Permission != "":
+		for _, file := range self.manifests {
+			for i, line := range file.Lines {
+				if strings.Contains(line, `"`+f.Permission+`"`) {
+					return Location{File: file.Path, Line: i + 1}, true
+				}
+			}
+		}
+	case f.Coordinate != "":
+		group, name, ok := strings.Cut(f.Coordinate, ":")
+		if !ok {
+			return Location{}, false
+		}
+		d, ok := self.project.Declaration(group, name)
+		if !ok {
+			return Location{}, false
+		}
+		if d.CatalogFile != "" {
+			return Location{File: d.CatalogFile, Line: d.CatalogLine}, true
+		}
+		return Location{File: d.File, Line: d.Line}, true
+	}
+	return Location{}, false
+}
@@ -0,0 +1,145 @@
+package findings
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	. "lampa/internal/globals"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolUri      = "https://github.com/dector/lampa"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+	// Lets code scanning match the same finding between runs
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	Uri       string `json:"uri"`
+	UriBaseId string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSarif writes findings as SARIF 2.1.0 log.
+// Locations are relative to the project directory (`%SRCROOT%`).
+// Findings without location are reported at the fallback one (code scanning rejects results without locations).
+func WriteSarif(w io.Writer, rules []Rule, findings []Finding, fallback Location) error {
+	driver := sarifDriver{
+		Name:           "lampa",
+		Version:        G.Version,
+		InformationUri: toolUri,
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := map[string]int{}
+	for i, rule := range rules {
+		ruleIndex[rule.Id] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			Id:                   rule.Id,
+			Name:                 rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: string(rule.Level)},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, f := range findings {
+		idx, ok := ruleIndex[f.Rule.Id]
+		if !ok {
+			continue
+		}
+		result := sarifResult{
+			RuleId:    f.Rule.Id,
+			RuleIndex: idx,
+			Level:     string(f.Rule.Level),
+			Message:   sarifMessage{Text: f.Message},
+			PartialFingerprints: map[string]string{
+				"lampaSubject/v1": f.Rule.Id + "/" + f.Subject(),
+			},
+		}
+		at := f.Location
+		if at.IsEmpty() {
+			at = fallback
+		}
+		if !at.IsEmpty() {
+			location := sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{Uri: at.File, UriBaseId: "%SRCROOT%"},
+			}
+			if at.Line > 0 {
+				location.Region = &sarifRegion{StartLine: at.Line}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(log); err != nil {
+		return fmt.Errorf("could not write SARIF report: %v", err)
+	}
+	return nil
+}
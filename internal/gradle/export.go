@@ -0,0 +1,195 @@
+package gradle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"lampa/internal"
+	"lampa/internal/report"
+	"lampa/internal/repositories"
+)
+
+// InitScript is a file name of the bundled Gradle init script (see `internal.GetAsset`).
+const InitScript = "lampa.init.gradle"
+
+// Tasks registered in every project by the init script.
+const (
+	TaskName         = "lampaDependencies"
+	VariantsTaskName = "lampaVariants"
+)
+
+// TaskArgs returns Gradle arguments to export dependencies of modules into `outputDir`.
+func TaskArgs(initScript string, outputDir string, modules []string, configurations []string) []string {
+	result := []string{"--init-script", initScript}
+	for _, module := range modules {
+		result = append(result, ProjectPath(module)+":"+TaskName)
+	}
+	return append(result,
+		"-Plampa.configurations="+strings.Join(configurations, ","),
+		"-Plampa.output="+outputDir,
+	)
+}
+
+// ProjectPath converts module name (e.g. "app" or "feature:home") to Gradle project path.
+func ProjectPath(module string) string {
+	return ":" + strings.TrimPrefix(module, ":")
+}
+
+// ProjectExport is an output of `lampaDependencies` task for a single project.
+type ProjectExport struct {
+	GradleVersion string
+	// Project path (e.g. ":app")
+	Project string
+	// All projects of the build
+	Modules []string
+	// Build variants (Android) of the project
+	Variants                 []string
+	ResolvableConfigurations []string
+	Repositories             []Repository
+	Configurations           []Configuration
+}
+
+type Repository struct {
+	Name string
+	Url  string
+	// Internal Gradle repository id (used by resolved components since Gradle 8)
+	Id string
+}
+
+type Configuration struct {
+	Name string
+	// Direct dependencies with resolved transitive graph
+	Dependencies []Node
+	// All resolved external modules
+	Components []Component
+}
+
+// Node is a dependency edge in resolved graph.
+type Node struct {
+	// Requested module (resolved one is in Group/Module/Version if it was substituted)
+	Group     string
+	Module    string
+	Version   string
+	Requested string
+
+	// Project path for project dependencies
+	Project string
+
+	Constraint bool
+	Unresolved bool
+
+	Children []Node
+}
+
+type Component struct {
+	Group   string
+	Module  string
+	Version string
+	// Repository id or name
+	Repository string
+	Artifacts  []Artifact
+}
+
+type Artifact struct {
+	Name string
+	Path string
+}
+
+func (self Component) Coordinate() string {
+	return fmt.Sprintf("%s:%s:%s", self.Group, self.Module, self.Version)
+}
+
+// Export holds exported data of all requested projects.
+type Export []ProjectExport
+
+// ReadExport reads all project files produced by the init script in `dir`.
+func ReadExport(dir string) (Export, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.dependencies.json"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+
+	result := Export{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("could not read `%s`: %v", file, err)
+		}
+
+		project := ProjectExport{}
+		if err := json.Unmarshal(data, &project); err != nil {
+			return nil, fmt.Errorf("could not parse `%s`: %v", file, err)
+		}
+		result = append(result, project)
+	}
+	return result, nil
+}
+
+// Project returns exported data of the module (e.g. "app").
+func (self Export) Project(module string) (ProjectExport, bool) {
+	path := ProjectPath(module)
+	for _, p := range self {
+		if p.Project == path {
+			return p, true
+		}
+	}
+	return ProjectExport{}, false
+}
+
+// Component returns resolved component ("group:name:version") from any project and configuration.
+func (self Export) Component(coordinate string) (Component, ProjectExport, bool) {
+	for _, p := range self {
+		for _, c := range p.Configurations {
+			for _, component := range c.Components {
+				if component.Coordinate() == coordinate {
+					return component, p, true
+				}
+			}
+		}
+	}
+	return Component{}, ProjectExport{}, false
+}
+
+// RepositoryOf returns repository that resolved the component ("group:name:version").
+func (self Export) RepositoryOf(coordinate string) (report.RepositorySegment, bool) {
+	component, project, ok := self.Component(coordinate)
+	if !ok || component.Repository == "" {
+		return report.RepositorySegment{}, false
+	}
+
+	ref := component.Repository
+	for _, repo := range project.Repositories {
+		if repo.Id == ref || repo.Name == ref {
+			return report.RepositorySegment{
+				Name: repositories.DisplayName(repo.Name, repo.Url),
+				Url:  repo.Url,
+			}, true
+		}
+	}
+
+	// Repository is not declared in the project (e.g. added by a plugin): keep the reference
+	return report.RepositorySegment{Name: ref}, true
+}
+
+func (self ProjectExport) Configuration(name string) (Configuration, bool) {
+	for _, c := range self.Configurations {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Configuration{}, false
+}
+
+// WriteInitScript writes the bundled init script into `dir` and returns its path.
+func WriteInitScript(dir string) (string, error) {
+	path := filepath.Join(dir, InitScript)
+	if err := os.WriteFile(path, internal.GetAsset(InitScript), 0644); err != nil {
+		return "", fmt.Errorf("could not write init script `%s`: %v", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,148 @@
+package gradle
+
+import (
+	"lampa/internal"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleExport = `{
+  "gradleVersion": "8.10",
+  "project": ":app",
+  "modules": [":", ":app", ":core:data"],
+  "variants": ["debug", "release"],
+  "resolvableConfigurations": ["debugRuntimeClasspath", "releaseRuntimeClasspath"],
+  "repositories": [
+    {"name": "Google", "url": "https://dl.google.com/dl/android/maven2/", "id": "a1b2"},
+    {"name": "MavenRepo", "url": "https://repo.maven.apache.org/maven2/", "id": null}
+  ],
+  "configurations": [{
+    "name": "releaseRuntimeClasspath",
+    "dependencies": [
+      {"group": "androidx.compose", "module": "compose-bom", "version": "2024.10.00", "requested": "2024.10.00", "constraint": false, "children": [
+        {"group": "androidx.compose.ui", "module": "ui", "version": "1.7.4", "requested": "1.7.4", "constraint": true},
+        {"group": "androidx.compose.foundation", "module": "foundation", "version": "1.7.4", "requested": "1.7.4", "constraint": true}
+      ]},
+      {"project": ":core:data", "constraint": false, "children": [
+        {"group": "com.squareup.okio", "module": "okio", "version": "3.9.0", "requested": "3.6.0", "constraint": false, "children": []}
+      ]},
+      {"group": "com.example", "module": "missing", "requested": "1.0", "constraint": false, "unresolved": true}
+    ],
+    "components": [
+      {"group": "androidx.compose", "module": "compose-bom", "version": "2024.10.00", "repository": "a1b2", "artifacts": []},
+      {"group": "androidx.compose.ui", "module": "ui", "version": "1.7.4", "repository": "a1b2", "artifacts": [
+        {"name": "ui-release.aar", "path": "/cache/ui-release.aar"}
+      ]},
+      {"group": "com.squareup.okio", "module": "okio", "version": "3.9.0", "repository": "MavenRepo", "artifacts": []},
+      {"group": "com.example", "module": "plugin-lib", "version": "1.0", "repository": "unknown", "artifacts": []}
+    ]
+  }]
+}`
+
+func readSampleExport(t *testing.T) Export {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.dependencies.json"), []byte(sampleExport), 0644); err != nil {
+		t.Fatal(err)
+	}
+	export, err := ReadExport(dir)
+	if err != nil {
+		t.Fatalf("ReadExport returned error: %v", err)
+	}
+	return export
+}
+
+func TestReadExport(t *testing.T) {
+	export := readSampleExport(t)
+
+	project, ok := export.Project("app")
+	if !ok {
+		t.Fatalf("Expected project :app, got: %#v", export)
+	}
+	if len(project.Variants) != 2 || len(project.Modules) != 3 {
+		t.Errorf("Unexpected project data: %#v", project)
+	}
+	if _, ok := project.Configuration("debugRuntimeClasspath"); ok {
+		t.Errorf("Expected configuration that wasn't exported to be missing")
+	}
+
+	component, _, ok := export.Component("androidx.compose.ui:ui:1.7.4")
+	if !ok || len(component.Artifacts) != 1 || component.Artifacts[0].Path != "/cache/ui-release.aar" {
+		t.Errorf("Unexpected component: %#v", component)
+	}
+}
+
+func TestConfiguration_Tree(t *testing.T) {
+	project, _ := readSampleExport(t).Project(":app")
+	configuration, ok := project.Configuration("releaseRuntimeClasspath")
+	if !ok {
+		t.Fatalf("Expected configuration to be exported")
+	}
+
+	tree := configuration.Tree()
+	if len(tree.Summary) != 4 {
+		t.Errorf("Expected 4 resolved components, got: %#v", tree.Summary)
+	}
+	if len(tree.Root.Children) != 2 {
+		t.Fatalf("Expected unresolved dependency to be skipped, got: %#v", tree.Root.Children)
+	}
+	if !tree.Root.Children[1].IsAModule || tree.Root.Children[1].ArtifactID != ":core:data" {
+		t.Errorf("Expected project dependency, got: %#v", tree.Root.Children[1])
+	}
+
+	platforms := internal.FindPlatforms(tree)
+	if len(platforms) != 1 || platforms[0].Coordinate() != "androidx.compose:compose-bom" {
+		t.Errorf("Unexpected platforms: %#v", platforms)
+	}
+
+	requests := internal.VersionRequests{}
+	requests.Add(tree, "app")
+	consumers := requests.Consumers("com.squareup.okio:okio", "3.6.0")
+	if len(consumers) != 1 || consumers[0] != "project :core:data" {
+		t.Errorf("Unexpected consumers: %v", consumers)
+	}
+
+	unresolved := configuration.Unresolved()
+	if len(unresolved) != 1 || unresolved[0] != "com.example:missing:1.0" {
+		t.Errorf("Unexpected unresolved dependencies: %v", unresolved)
+	}
+}
+
+func TestExport_RepositoryOf(t *testing.T) {
+	export := readSampleExport(t)
+
+	repo, ok := export.RepositoryOf("androidx.compose.ui:ui:1.7.4")
+	if !ok || repo.Name != "Google Maven" {
+		t.Errorf("Unexpected repository resolved by id: %#v", repo)
+	}
+	repo, ok = export.RepositoryOf("com.squareup.okio:okio:3.9.0")
+	if !ok || repo.Name != "Maven Central" {
+		t.Errorf("Unexpected repository resolved by name: %#v", repo)
+	}
+	repo, ok = export.RepositoryOf("com.example:plugin-lib:1.0")
+	if !ok || repo.Name != "unknown" || repo.Url != "" {
+		t.Errorf("Unexpected undeclared repository: %#v", repo)
+	}
+	if _, ok := export.RepositoryOf("com.example:missing:1.0"); ok {
+		t.Errorf("Expected no repository for unresolved component")
+	}
+}
+
+func TestTaskArgs(t *testing.T) {
+	args := TaskArgs("/tmp/lampa.init.gradle", "/tmp/out", []string{"app", ":feature:home"}, []string{"releaseRuntimeClasspath"})
+	expected := []string{
+		"--init-script", "/tmp/lampa.init.gradle",
+		":app:lampaDependencies", ":feature:home:lampaDependencies",
+		"-Plampa.configurations=releaseRuntimeClasspath",
+		"-Plampa.output=/tmp/out",
+	}
+	if len(args) != len(expected) {
+		t.Fatalf("Unexpected args: %v", args)
+	}
+	for i := range expected {
+		if args[i] != expected[i] {
+			t.Errorf("Unexpected args: %v", args)
+			break
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package gradle
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+
+	"lampa/internal/proc"
+)
+
+// Runner executes Gradle wrapper of the project.
+type Runner struct {
+	GradlewPath string
+	ProjectDir  string
+
+	// Keep Gradle daemon running after invocation (to speed up next runs)
+	UseDaemon bool
+
+	// Called for every line of Gradle output (stdout and stderr)
+	OnOutput func(line string)
+}
+
+// Run executes all tasks in a single Gradle invocation and returns combined output.
+// Gradle is stopped when context is cancelled.
+func (self Runner) Run(ctx context.Context, args ...string) ([]byte, error) {
+	daemonArg := "--no-daemon"
+	if self.UseDaemon {
+		daemonArg = "--daemon"
+	}
+
+	cmd := proc.Command(ctx,
+		self.GradlewPath,
+		append([]string{daemonArg, "--console", "plain"}, args...)...,
+	)
+	cmd.Dir = self.ProjectDir
+
+	output := &lineWriter{onLine: self.OnOutput}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err := cmd.Run()
+	output.Flush()
+	return output.buffer.Bytes(), err
+}
+
+// lineWriter keeps all written data and reports it line by line.
+type lineWriter struct {
+	mu      sync.Mutex
+	buffer  bytes.Buffer
+	pending []byte
+	onLine  func(line string)
+}
+
+func (self *lineWriter) Write(p []byte) (int, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.buffer.Write(p)
+	if self.onLine == nil {
+		return len(p), nil
+	}
+
+	self.pending = append(self.pending, p...)
+	for {
+		idx := bytes.IndexByte(self.pending, '\n')
+		if idx < 0 {
+			break
+		}
+		self.onLine(strings.TrimRight(string(self.pending[:idx]), "\r"))
+		self.pending = self.pending[idx+1:]
+	}
+	return len(p), nil
+}
+
+// Flush reports the last line if it wasn't terminated.
+func (self *lineWriter) Flush() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.onLine != nil && len(self.pending) > 0 {
+		self.onLine(string(self.pending))
+		self.pending = nil
+	}
+}
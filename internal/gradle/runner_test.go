@@ -0,0 +1,65 @@
+package gradle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func fakeGradlew(t *testing.T, script string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gradlew")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunner_Run(t *testing.T) {
+	gradlew := fakeGradlew(t, `echo "args: $*"
+echo "> Task :app:preBuild UP-TO-DATE"
+echo "warning" >&2
+printf "> Task :app:bundleRelease"
+`)
+
+	lines := []string{}
+	runner := Runner{
+		GradlewPath: gradlew,
+		ProjectDir:  filepath.Dir(gradlew),
+		OnOutput: func(line string) {
+			lines = append(lines, line)
+		},
+	}
+	output, err := runner.Run(context.Background(), "bundleRelease", ":app:lampaDependencies")
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if !strings.Contains(string(output), "args: --no-daemon --console plain bundleRelease :app:lampaDependencies") {
+		t.Errorf("Unexpected Gradle arguments:\n%s", output)
+	}
+	if !strings.Contains(string(output), "warning") {
+		t.Errorf("Expected stderr in output:\n%s", output)
+	}
+	if len(lines) != 4 || lines[3] != "> Task :app:bundleRelease" {
+		t.Errorf("Unexpected reported lines: %q", lines)
+	}
+}
+
+func TestRunner_RunWithDaemon(t *testing.T) {
+	gradlew := fakeGradlew(t, `echo "$1"; exit 3`)
+
+	output, err := Runner{GradlewPath: gradlew, UseDaemon: true}.Run(context.Background(), "help")
+	if err == nil {
+		t.Errorf("Expected error for failed Gradle execution")
+	}
+	if strings.TrimSpace(string(output)) != "--daemon" {
+		t.Errorf("Expected daemon to be enabled, got: %s", output)
+	}
+}
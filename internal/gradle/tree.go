@@ -0,0 +1,61 @@
+package gradle
+
+import (
+	"lampa/internal"
+)
+
+// Tree converts resolved graph to the dependency tree (same as parsed from `dependencies` task output).
+func (self Configuration) Tree() internal.DependenciesTree {
+	result := internal.DependenciesTree{}
+	result.Root.Children = toDependencies(self.Dependencies)
+
+	for _, c := range self.Components {
+		result.Summary = append(result.Summary, internal.Dependency{
+			GroupID:    c.Group,
+			ArtifactID: c.Module,
+			Version:    c.Version,
+		})
+	}
+	return result
+}
+
+// Unresolved returns dependencies that Gradle failed to resolve.
+func (self Configuration) Unresolved() []string {
+	result := []string{}
+	var walk func(nodes []Node)
+	walk = func(nodes []Node) {
+		for _, n := range nodes {
+			if n.Unresolved {
+				result = append(result, n.Group+":"+n.Module+":"+n.Requested)
+			}
+			walk(n.Children)
+		}
+	}
+	walk(self.Dependencies)
+	return result
+}
+
+func toDependencies(nodes []Node) []internal.Dependency {
+	result := []internal.Dependency{}
+	for _, n := range nodes {
+		if n.Unresolved {
+			continue
+		}
+
+		d := internal.Dependency{
+			IsAConstraint: n.Constraint,
+			Children:      toDependencies(n.Children),
+		}
+		if n.Project != "" {
+			d.ArtifactID = n.Project
+			d.IsAModule = true
+		} else {
+			d.GroupID = n.Group
+			d.ArtifactID = n.Module
+			d.Version = n.Version
+			d.RequestedVersion = n.Requested
+		}
+		result = append(result, d)
+	}
+	return result
+}
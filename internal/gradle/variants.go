@@ -0,0 +1,129 @@
+package gradle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// VariantsExport is an output of `lampaVariants` task for a single project.
+type VariantsExport struct {
+	Project    string
+	Variants   []Variant
+	BuildTypes []string
+	Flavors    []Flavor
+}
+
+type Variant struct {
+	Name string
+	// Empty for non-Android projects
+	BuildType string
+	Flavors   []string
+}
+
+type Flavor struct {
+	Name      string
+	Dimension string
+}
+
+// VariantsTaskArgs returns Gradle arguments to export variants of the module into `outputDir`.
+func VariantsTaskArgs(initScript string, outputDir string, module string) []string {
+	return []string{
+		"--init-script", initScript,
+		ProjectPath(module) + ":" + VariantsTaskName,
+		"-Plampa.output=" + outputDir,
+	}
+}
+
+// ReadVariants reads variants of the module exported by the init script in `dir`.
+func ReadVariants(dir string, module string) (VariantsExport, error) {
+	name := strings.ReplaceAll(strings.TrimPrefix(ProjectPath(module), ":"), ":", "_")
+	if name == "" {
+		name = "root"
+	}
+	file := filepath.Join(dir, name+".variants.json")
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return VariantsExport{}, fmt.Errorf("could not read `%s`: %v", file, err)
+	}
+	result := VariantsExport{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return VariantsExport{}, fmt.Errorf("could not parse `%s`: %v", file, err)
+	}
+	return result, nil
+}
+
+// ListVariants runs `lampaVariants` task of the module and returns its variants.
+func (self Runner) ListVariants(ctx context.Context, module string) (VariantsExport, []byte, error) {
+	tempDir, err := os.MkdirTemp("", "lampa-gradle")
+	if err != nil {
+		return VariantsExport{}, nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	initScript, err := WriteInitScript(tempDir)
+	if err != nil {
+		return VariantsExport{}, nil, err
+	}
+	outputDir := filepath.Join(tempDir, "out")
+
+	output, err := self.Run(ctx, VariantsTaskArgs(initScript, outputDir, module)...)
+	if err != nil {
+		return VariantsExport{}, output, err
+	}
+
+	result, err := ReadVariants(outputDir, module)
+	return result, output, err
+}
+
+// BundleTask returns name of the task that builds App Bundle of the variant (e.g. "bundleProdRelease").
+func BundleTask(variant string) string {
+	r, size := utf8.DecodeRuneInString(variant)
+	return "bundle" + string(unicode.ToUpper(r)) + variant[size:]
+}
+
+func (self VariantsExport) Names() []string {
+	result := []string{}
+	for _, v := range self.Variants {
+		result = append(result, v.Name)
+	}
+	return result
+}
+
+// IsPattern reports whether variant contains glob characters (e.g. "*Release").
+func IsPattern(variant string) bool {
+	return strings.ContainsAny(variant, "*?[")
+}
+
+// MatchVariants expands glob patterns against available variants (keeping order of patterns).
+// Every pattern must match at least one variant.
+func MatchVariants(patterns []string, available []string) ([]string, error) {
+	result := []string{}
+	for _, pattern := range patterns {
+		matched := false
+		for _, v := range available {
+			ok, err := path.Match(pattern, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid variant pattern %q: %v", pattern, err)
+			}
+			if ok {
+				matched = true
+				if !slices.Contains(result, v) {
+					result = append(result, v)
+				}
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no variants match %q (available: %s)", pattern, strings.Join(available, ", "))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package gradle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadVariants(t *testing.T) {
+	dir := t.TempDir()
+	data := `{
+  "project": ":app",
+  "variants": [
+    {"name": "demoDebug", "buildType": "debug", "flavors": ["demo"]},
+    {"name": "prodRelease", "buildType": "release", "flavors": ["prod"]}
+  ],
+  "buildTypes": ["debug", "release"],
+  "flavors": [{"name": "demo", "dimension": "contentType"}, {"name": "prod", "dimension": "contentType"}]
+}`
+	if err := os.WriteFile(filepath.Join(dir, "app.variants.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	variants, err := ReadVariants(dir, "app")
+	if err != nil {
+		t.Fatalf("ReadVariants returned error: %v", err)
+	}
+	names := variants.Names()
+	if len(names) != 2 || names[1] != "prodRelease" {
+		t.Errorf("Unexpected variants: %v", names)
+	}
+	if len(variants.Flavors) != 2 || variants.Flavors[0].Dimension != "contentType" {
+		t.Errorf("Unexpected flavors: %#v", variants.Flavors)
+	}
+}
+
+func TestMatchVariants(t *testing.T) {
+	available := []string{"demoDebug", "demoRelease", "prodDebug", "prodRelease"}
+
+	matched, err := MatchVariants([]string{"prodRelease", "*Release"}, available)
+	if err != nil {
+		t.Fatalf("MatchVariants returned error: %v", err)
+	}
+	if len(matched) != 2 || matched[0] != "prodRelease" || matched[1] != "demoRelease" {
+		t.Errorf("Unexpected variants: %v", matched)
+	}
+
+	if _, err := MatchVariants([]string{"*Staging"}, available); err == nil {
+		t.Errorf("Expected error for pattern without matches")
+	}
+}
+
+func TestBundleTask(t *testing.T) {
+	cases := map[string]string{
+		"release":       "bundleRelease",
+		"prodRelease":   "bundleProdRelease",
+		"demoFreeDebug": "bundleDemoFreeDebug",
+	}
+	for variant, expected := range cases {
+		if actual := BundleTask(variant); actual != expected {
+			t.Errorf("BundleTask(%q) = %q, expected %q", variant, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package gradlecache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lampa/internal/maven"
+	"lampa/internal/utils"
+)
+
+// DefaultRoot returns location of Gradle user home.
+func DefaultRoot() string {
+	if home := strings.TrimSpace(os.Getenv("GRADLE_USER_HOME")); home != "" {
+		return utils.TryResolveFsPath(home)
+	}
+	return utils.TryResolveFsPath("~/.gradle")
+}
+
+// Cache gives access to artifacts in Gradle user home (`caches/modules-2/files-2.1`).
+type Cache struct {
+	Root string
+}
+
+func New(gradleHome string) Cache {
+	return Cache{Root: gradleHome}
+}
+
+func (self Cache) filesDir() string {
+	return filepath.Join(self.Root, "caches", "modules-2", "files-2.1")
+}
+
+// VersionDir returns directory with artifact files (grouped by SHA1 subdirectories).
+func (self Cache) VersionDir(group string, artifact string, version string) string {
+	return filepath.Join(self.filesDir(), group, artifact, version)
+}
+
+// FindFiles returns paths of cached files of the artifact with extension (e.g. ".pom" or ".aar").
+func (self Cache) FindFiles(group string, artifact string, version string, ext string) []string {
+	pattern := filepath.Join(self.VersionDir(group, artifact, version), "*", "*"+ext)
+	files, _ := filepath.Glob(pattern)
+	return files
+}
+
+// FindPom returns path to the POM file of the artifact.
+func (self Cache) FindPom(group string, artifact string, version string) (string, bool) {
+	files := self.FindFiles(group, artifact, version, ".pom")
+	if len(files) == 0 {
+		return "", false
+	}
+	return files[0], true
+}
+
+// ReadPom reads POM of the artifact (with fields inherited from parent POMs).
+func (self Cache) ReadPom(group string, artifact string, version string) (maven.Pom, bool) {
+	pom, ok := self.readPom(group, artifact, version)
+	if !ok {
+		return maven.Pom{}, false
+	}
+	return pom.WithInheritedFields(self.readPom), true
+}
+
+func (self Cache) readPom(group string, artifact string, version string) (maven.Pom, bool) {
+	path, ok := self.FindPom(group, artifact, version)
+	if !ok {
+		return maven.Pom{}, false
+	}
+	pom, err := maven.ReadPom(path)
+	return pom, err == nil
+}
+
+// ArtifactFile is a cached file of the artifact.
+type ArtifactFile struct {
+	Name string
+	Path string
+}
+
+var artifactExtensions = []string{".aar", ".jar", ".pom", ".module"}
+
+// FindArtifactFiles returns cached binaries and metadata files of the artifact
+// (sources and javadoc are skipped). The same file is returned several times if it is cached
+// in several SHA1 directories (e.g. artifact was republished with the same version).
+func (self Cache) FindArtifactFiles(group string, artifact string, version string) []ArtifactFile {
+	result := []ArtifactFile{}
+	for _, ext := range artifactExtensions {
+		for _, path := range self.FindFiles(group, artifact, version, ext) {
+			name := filepath.Base(path)
+			if strings.HasSuffix(name, "-sources.jar") || strings.HasSuffix(name, "-javadoc.jar") {
+				continue
+			}
+			result = append(result, ArtifactFile{Name: name, Path: path})
+		}
+	}
+	return result
+}
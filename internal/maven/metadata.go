@@ -0,0 +1,104 @@
+package maven
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"lampa/internal/versions"
+)
+
+// Metadata is a content of `maven-metadata.xml` file.
+type Metadata struct {
+	XMLName    xml.Name `xml:"metadata"`
+	GroupId    string   `xml:"groupId"`
+	ArtifactId string   `xml:"artifactId"`
+	Versioning struct {
+		Latest   string   `xml:"latest"`
+		Release  string   `xml:"release"`
+		Versions []string `xml:"versions>version"`
+	} `xml:"versioning"`
+}
+
+func ReadMetadata(path string) (Metadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	var result Metadata
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return Metadata{}, fmt.Errorf("could not parse %s: %v", path, err)
+	}
+	return result, nil
+}
+
+// ArtifactDir returns directory of the artifact in Maven repository layout.
+func ArtifactDir(repoRoot string, group string, artifact string) string {
+	parts := append([]string{repoRoot}, strings.Split(group, ".")...)
+	return filepath.Join(append(parts, artifact)...)
+}
+
+// FindVersions collects all known versions of the artifact in local repositories
+// (`~/.m2/repository` or mirror with the same layout).
+// Versions are read from `maven-metadata*.xml` files and version directories.
+func FindVersions(repoRoots []string, group string, artifact string) []string {
+	result := []string{}
+
+	for _, root := range repoRoots {
+		dir := ArtifactDir(root, group, artifact)
+
+		files, _ := filepath.Glob(filepath.Join(dir, "maven-metadata*.xml"))
+		for _, f := range files {
+			metadata, err := ReadMetadata(f)
+			if err != nil {
+				continue
+			}
+			result = append(result, metadata.Versioning.Versions...)
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				result = append(result, e.Name())
+			}
+		}
+	}
+
+	result = slices.DeleteFunc(result, func(v string) bool {
+		return strings.TrimSpace(v) == ""
+	})
+	slices.SortFunc(result, versions.Compare)
+	return slices.Compact(result)
+}
+
+// LatestVersions returns the newest stable and the newest pre-release version
+// (pre-release only if it's newer than the stable one).
+func LatestVersions(all []string) (stable string, preRelease string) {
+	for _, v := range all {
+		if versions.Classify(v) == versions.GitHash {
+			continue
+		}
+
+		if versions.Classify(v).IsStable() {
+			if stable == "" || versions.IsLater(v, stable) {
+				stable = v
+			}
+		} else {
+			if preRelease == "" || versions.IsLater(v, preRelease) {
+				preRelease = v
+			}
+		}
+	}
+
+	if stable != "" && preRelease != "" && !versions.IsLater(preRelease, stable) {
+		preRelease = ""
+	}
+	return stable, preRelease
+}
@@ -0,0 +1,52 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindVersions(t *testing.T) {
+	root := t.TempDir()
+	dir := ArtifactDir(root, "com.google.guava", "guava")
+	if err := os.MkdirAll(filepath.Join(dir, "32.0.0-android"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	metadata := `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>com.google.guava</groupId>
+  <artifactId>guava</artifactId>
+  <versioning>
+    <latest>33.3.0-android</latest>
+    <release>33.3.0-android</release>
+    <versions>
+      <version>33.2.1-android</version>
+      <version>33.3.0-android</version>
+      <version>33.2.1-android</version>
+    </versions>
+  </versioning>
+</metadata>`
+	if err := os.WriteFile(filepath.Join(dir, "maven-metadata-central.xml"), []byte(metadata), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := FindVersions([]string{root, filepath.Join(root, "missing")}, "com.google.guava", "guava")
+
+	expected := []string{"32.0.0-android", "33.2.1-android", "33.3.0-android"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestLatestVersions(t *testing.T) {
+	stable, pre := LatestVersions([]string{"1.0.0", "1.1.0-alpha01", "1.0.1", "1.1.0-beta02"})
+	if stable != "1.0.1" || pre != "1.1.0-beta02" {
+		t.Errorf("Unexpected latest versions: %s, %s", stable, pre)
+	}
+
+	stable, pre = LatestVersions([]string{"1.0.0-rc01", "1.0.0"})
+	if stable != "1.0.0" || pre != "" {
+		t.Errorf("Unexpected latest versions: %s, %s", stable, pre)
+	}
+}
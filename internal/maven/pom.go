@@ -0,0 +1,136 @@
+package maven
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Pom is a subset of POM file fields.
+type Pom struct {
+	XMLName     xml.Name `xml:"project"`
+	GroupId     string   `xml:"groupId"`
+	ArtifactId  string   `xml:"artifactId"`
+	Version     string   `xml:"version"`
+	Name        string   `xml:"name"`
+	Description string   `xml:"description"`
+	Url         string   `xml:"url"`
+
+	Parent struct {
+		GroupId    string `xml:"groupId"`
+		ArtifactId string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	} `xml:"parent"`
+
+	Organization struct {
+		Name string `xml:"name"`
+		Url  string `xml:"url"`
+	} `xml:"organization"`
+
+	Scm struct {
+		Url                 string `xml:"url"`
+		Connection          string `xml:"connection"`
+		DeveloperConnection string `xml:"developerConnection"`
+		Tag                 string `xml:"tag"`
+	} `xml:"scm"`
+
+	Developers []struct {
+		Id           string `xml:"id"`
+		Name         string `xml:"name"`
+		Email        string `xml:"email"`
+		Organization string `xml:"organization"`
+	} `xml:"developers>developer"`
+}
+
+func ReadPom(path string) (Pom, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Pom{}, fmt.Errorf("could not read %s: %v", path, err)
+	}
+
+	var result Pom
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return Pom{}, fmt.Errorf("could not parse %s: %v", path, err)
+	}
+	return result, nil
+}
+
+// ScmUrl returns browsable repository URL (connection strings like "scm:git:git://..." are converted).
+func (self Pom) ScmUrl() string {
+	for _, s := range []string{self.Scm.Url, self.Scm.Connection, self.Scm.DeveloperConnection} {
+		if url := normalizeScmUrl(s); url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+func normalizeScmUrl(s string) string {
+	s = strings.TrimSpace(s)
+	// longer prefixes go first, so "git://" is not cut as "git:"
+	prefixes := []string{"scm:", "ssh://git@", "git://", "https://", "http://", "git@", "git:"}
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, prefix := range prefixes {
+			if rest, ok := strings.CutPrefix(s, prefix); ok {
+				s, trimmed = rest, true
+				break
+			}
+		}
+	}
+	s = strings.Replace(s, ":", "/", 1)
+	s = strings.TrimSuffix(s, "/")
+	s = strings.TrimSuffix(s, ".git")
+	if s == "" || strings.Contains(s, "${") {
+		return ""
+	}
+	return "https://" + s
+}
+
+// GitHubRepository returns "owner/repo" if SCM is hosted on GitHub.
+func (self Pom) GitHubRepository() (string, bool) {
+	url := self.ScmUrl()
+	rest, ok := strings.CutPrefix(url, "https://github.com/")
+	if !ok {
+		return "", false
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1], true
+}
+
+// PomLookup finds POM of the artifact.
+type PomLookup func(group string, artifact string, version string) (Pom, bool)
+
+const maxParentDepth = 5
+
+// WithInheritedFields fills missing project information from parent POMs.
+func (self Pom) WithInheritedFields(lookup PomLookup) Pom {
+	result := self
+	parent := self
+	for i := 0; i < maxParentDepth && parent.Parent.ArtifactId != ""; i++ {
+		var ok bool
+		parent, ok = lookup(parent.Parent.GroupId, parent.Parent.ArtifactId, parent.Parent.Version)
+		if !ok {
+			break
+		}
+
+		if result.Url == "" {
+			result.Url = parent.Url
+		}
+		if result.Organization.Name == "" && result.Organization.Url == "" {
+			result.Organization = parent.Organization
+		}
+		if result.ScmUrl() == "" {
+			result.Scm = parent.Scm
+		}
+		if len(result.Developers) == 0 {
+			result.Developers = parent.Developers
+		}
+	}
+	return result
+}
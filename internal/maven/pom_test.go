@@ -0,0 +1,51 @@
+package maven
+
+import "testing"
+
+func TestPom_ScmUrl(t *testing.T) {
+	cases := map[string]string{
+		"https://github.com/square/okhttp/":                    "https://github.com/square/okhttp",
+		"scm:git:git@github.com:square/okhttp.git":             "https://github.com/square/okhttp",
+		"scm:git:https://github.com/google/guava.git":          "https://github.com/google/guava",
+		"scm:git:git://github.com/JetBrains/kotlin.git":        "https://github.com/JetBrains/kotlin",
+		"https://android.googlesource.com/platform/frameworks": "https://android.googlesource.com/platform/frameworks",
+		"git://github.com/x/y":                                 "https://github.com/x/y",
+		"ssh://git@github.com/x/y.git":                         "https://github.com/x/y",
+		"${project.scm.url}":                                   "",
+	}
+
+	for input, expected := range cases {
+		pom := Pom{}
+		pom.Scm.Url = input
+		if actual := pom.ScmUrl(); actual != expected {
+			t.Errorf("ScmUrl() for %q expected to be %q, got %q", input, expected, actual)
+		}
+	}
+}
+
+func TestPom_WithInheritedFields(t *testing.T) {
+	parent := Pom{Url: "https://square.github.io/okhttp/"}
+	parent.Organization.Name = "Square, Inc."
+	parent.Scm.Url = "https://github.com/square/okhttp"
+
+	child := Pom{Name: "okhttp"}
+	child.Parent.GroupId = "com.squareup.okhttp3"
+	child.Parent.ArtifactId = "parent"
+	child.Parent.Version = "4.12.0"
+
+	lookup := func(group string, artifact string, version string) (Pom, bool) {
+		if group == "com.squareup.okhttp3" && artifact == "parent" && version == "4.12.0" {
+			return parent, true
+		}
+		return Pom{}, false
+	}
+
+	result := child.WithInheritedFields(lookup)
+
+	if result.Name != "okhttp" || result.Url != parent.Url || result.Organization.Name != "Square, Inc." {
+		t.Errorf("Unexpected POM: %+v", result)
+	}
+	if result.ScmUrl() != "https://github.com/square/okhttp" {
+		t.Errorf("Expected SCM to be inherited, got: %s", result.ScmUrl())
+	}
+}
@@ -25,6 +25,8 @@ type Dependency struct {
 
 	// TODO change model
 	IsAModule bool
+	// Dependency constraint (e.g. declared by platform/BOM), marked with "(c)"
+	IsAConstraint bool
 }
 
 func (d Dependency) String() string {
@@ -51,20 +53,6 @@ func (d Dependency) IsEquals(other Dependency) bool {
 		d.RequestedVersion == other.RequestedVersion
 }
 
-// FIXME add error handling
-func ParseTreeFromOutput(output string, name string) (DependenciesTree, error) {
-	// TODO improve with iterator
-	lines := strings.Split(output, "\n")
-	_, startIdx, _ := lo.FindIndexOf(lines, func(it string) bool {
-		return strings.HasPrefix(it, name)
-	})
-	_, endIdx, _ := lo.FindIndexOf(lines[startIdx:], func(it string) bool {
-		return strings.TrimSpace(it) == ""
-	})
-
-	return ParseTree(strings.Join(lines[startIdx+1:endIdx], "\n"))
-}
-
 func ParseTree(source string) (DependenciesTree, error) {
 	result := DependenciesTree{}
 
@@ -123,6 +111,8 @@ func parseDependencyLine(line string) (ParsedDependency, error) {
 
 	parts := strings.Fields(line)
 
+	result.Dependency.IsAConstraint = lo.Contains(parts, "(c)")
+
 	// Parse level
 	result.Level = lo.CountBy(parts, func(it string) bool {
 		return IsATreeMarker(it)
@@ -268,3 +268,93 @@ func TestParseDependency_Project(t *testing.T) {
 		t.Errorf("Parsed dependency does not match expected structure.\nGot: %#v\nWant: %#v", actual, expected)
 	}
 }
+
+func TestFindPlatforms(t *testing.T) {
+	input := `
++--- androidx.compose:compose-bom:2024.10.00
+|    +--- androidx.compose.ui:ui:1.7.4 (c)
+|    \--- androidx.compose.foundation:foundation:1.7.4 (c)
++--- androidx.compose.ui:ui -> 1.7.4
+|    +--- androidx.annotation:annotation:1.8.1 (*)
+|    \--- androidx.compose.ui:ui-android:1.7.4
+|         \--- androidx.compose:compose-bom:2024.10.00 (*)
++--- com.google.firebase:firebase-bom:33.7.0
+|    \--- com.google.firebase:firebase-analytics:22.1.2 (c)
+\--- androidx.compose.foundation:foundation:1.7.0 -> 1.7.4
+`
+	tree, err := ParseTree(input)
+	if err != nil {
+		t.Fatalf("ParseTree returned error: %v", err)
+	}
+
+	platforms := FindPlatforms(tree)
+	if len(platforms) != 2 {
+		t.Fatalf("Expected 2 platforms, got: %#v", platforms)
+	}
+	if platforms[0].Coordinate() != "androidx.compose:compose-bom" || len(platforms[0].Managed) != 2 {
+		t.Errorf("Unexpected platform: %#v", platforms[0])
+	}
+	if platforms[1].Coordinate() != "com.google.firebase:firebase-bom" || len(platforms[1].Managed) != 1 {
+		t.Errorf("Unexpected platform: %#v", platforms[1])
+	}
+
+	p, ok := FindManagingPlatform(platforms, Dependency{GroupID: "androidx.compose.ui", ArtifactID: "ui", Version: "1.7.4"})
+	if !ok || p.Coordinate() != "androidx.compose:compose-bom" {
+		t.Errorf("Expected dependency to be managed by compose-bom, got: %#v", p)
+	}
+
+	_, ok = FindManagingPlatform(platforms, Dependency{GroupID: "androidx.compose.ui", ArtifactID: "ui", Version: "1.8.0"})
+	if ok {
+		t.Errorf("Expected dependency with overridden version to be unmanaged")
+	}
+}
+
+func TestVersionRequests(t *testing.T) {
+	input := `
++--- com.google.dagger:hilt-android:2.56
+|    +--- com.google.dagger:dagger:2.56
+|    |    \--- jakarta.inject:jakarta.inject-api:2.0.1 -> 2.0.2
+|    \--- javax.inject:javax.inject:1
++--- project :core:data
+|    \--- jakarta.inject:jakarta.inject-api:2.0.2
++--- com.squareup.okhttp3:okhttp -> 4.12.0
++--- javax.inject:javax.inject:1 (c)
+\--- androidx.core:core:1.12.0 -> 1.13.0 (c)
+`
+	tree, err := ParseTree(input)
+	if err != nil {
+		t.Fatalf("ParseTree returned error: %v", err)
+	}
+
+	requests := VersionRequests{}
+	requests.Add(tree, "app")
+
+	versions := requests.Versions("jakarta.inject:jakarta.inject-api")
+	if len(versions) != 2 || versions[0] != "2.0.1" || versions[1] != "2.0.2" {
+		t.Errorf("Unexpected requested versions: %v", versions)
+	}
+
+	consumers := requests.Consumers("jakarta.inject:jakarta.inject-api", "2.0.1")
+	if len(consumers) != 1 || consumers[0] != "com.google.dagger:dagger:2.56" {
+		t.Errorf("Unexpected consumers: %v", consumers)
+	}
+	consumers = requests.Consumers("jakarta.inject:jakarta.inject-api", "2.0.2")
+	if len(consumers) != 1 || consumers[0] != "project :core:data" {
+		t.Errorf("Unexpected consumers: %v", consumers)
+	}
+	consumers = requests.Consumers("com.google.dagger:hilt-android", "2.56")
+	if len(consumers) != 1 || consumers[0] != "app" {
+		t.Errorf("Unexpected consumers: %v", consumers)
+	}
+
+	if versions := requests.Versions("com.squareup.okhttp3:okhttp"); len(versions) != 0 {
+		t.Errorf("Expected no requested versions for dependency without version, got: %v", versions)
+	}
+
+	if !requests.IsConstraintOnly("androidx.core:core", "1.12.0") {
+		t.Errorf("Expected version requested by constraint to be constraint-only")
+	}
+	if requests.IsConstraintOnly("javax.inject:javax.inject", "1") {
+		t.Errorf("Expected version requested by dependency and constraint not to be constraint-only")
+	}
+}
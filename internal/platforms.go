@@ -0,0 +1,70 @@
+package internal
+
+import "strings"
+
+// Platform is a dependency (BOM) that only declares version constraints for other dependencies.
+type Platform struct {
+	Dependency Dependency
+
+	Managed []Dependency
+}
+
+func (p Platform) Coordinate() string {
+	return p.Dependency.GroupID + ":" + p.Dependency.ArtifactID
+}
+
+// FindPlatforms looks for platform/BOM imports in the dependency tree.
+func FindPlatforms(tree DependenciesTree) []Platform {
+	result := []Platform{}
+	seen := map[string]bool{}
+
+	var walk func(node Dependency)
+	walk = func(node Dependency) {
+		for _, child := range node.Children {
+			if isAPlatform(child) {
+				if !seen[child.String()] {
+					seen[child.String()] = true
+					result = append(result, Platform{
+						Dependency: child,
+						Managed:    child.Children,
+					})
+				}
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(tree.Root)
+
+	return result
+}
+
+// FindManagingPlatform returns platform that defined resolved version of the dependency.
+func FindManagingPlatform(platforms []Platform, d Dependency) (Platform, bool) {
+	for _, p := range platforms {
+		for _, m := range p.Managed {
+			if m.GroupID == d.GroupID && m.ArtifactID == d.ArtifactID && m.Version == d.Version {
+				return p, true
+			}
+		}
+	}
+	return Platform{}, false
+}
+
+func isAPlatform(d Dependency) bool {
+	if d.IsAModule || d.IsAConstraint || len(d.Children) == 0 {
+		return false
+	}
+	for _, child := range d.Children {
+		if !child.IsAConstraint {
+			return false
+		}
+	}
+
+	// Regular libraries are also declaring constraints to align versions of their own artifacts
+	name := strings.ToLower(d.ArtifactID)
+	looksLikeAPlatform := strings.Contains(name, "bom") || strings.Contains(name, "platform")
+	return looksLikeAPlatform || len(d.Children) >= minPlatformConstraints
+}
+
+const minPlatformConstraints = 3
@@ -0,0 +1,52 @@
+package policy
+
+import (
+	"fmt"
+	"lampa/internal/config"
+	"lampa/internal/report"
+)
+
+type Violation struct {
+	Rule       string
+	Dependency report.CoordinatedDependency
+	Message    string
+}
+
+func (self Violation) String() string {
+	return fmt.Sprintf("[%s] %s", self.Rule, self.Message)
+}
+
+// Check evaluates policy rules against the report.
+// Ignored dependencies are skipped as the report could be collected before they were ignored.
+func Check(cfg config.Config, r *report.Report) []Violation {
+	violations := []Violation{}
+	rules := cfg.Policy
+
+	for _, d := range r.Build.Dependencies.Compile {
+		if config.MatchesAnyCoordinate(cfg.Ignore, d.Group, d.Name) {
+			continue
+		}
+
+		for _, pattern := range rules.Deny {
+			if config.MatchesCoordinate(pattern, d.Group, d.Name) {
+				violations = append(violations, Violation{
+					Rule:       "deny",
+					Dependency: d,
+					Message:    fmt.Sprintf("%s is denied by `%s`", d, pattern),
+				})
+				break
+			}
+		}
+
+		if rules.DenyUnstable && !d.Stability().IsStable() &&
+			!config.MatchesAnyCoordinate(rules.AllowUnstable, d.Group, d.Name) {
+			violations = append(violations, Violation{
+				Rule:       "deny-unstable",
+				Dependency: d,
+				Message:    fmt.Sprintf("%s is not stable (%s)", d, d.Stability().Label()),
+			})
+		}
+	}
+
+	return violations
+}
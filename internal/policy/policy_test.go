@@ -0,0 +1,139 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"lampa/internal/config"
+	"lampa/internal/report"
+)
+
+func reportWith(deps ...report.CoordinatedDependency) *report.Report {
+	r := &report.Report{}
+	r.Build.Dependencies.Compile = deps
+	return r
+}
+
+func dep(group, name, version string) report.CoordinatedDependency {
+	return report.CoordinatedDependency{Group: group, Name: name, Version: version}
+}
+
+func violated(violations []Violation) map[string]string {
+	result := map[string]string{}
+	for _, v := range violations {
+		result[v.Dependency.String()] = v.Rule
+	}
+	return result
+}
+
+func TestCheck_Deny(t *testing.T) {
+	cfg := config.Config{
+		Policy: config.PolicyConfig{
+			Deny: []string{"com.android.support:*", "junit:junit", "com.google.firebase", "org.jetbrains.*:*-jdk7"},
+		},
+	}
+	r := reportWith(
+		dep("com.android.support", "appcompat-v7", "28.0.0"),
+		dep("junit", "junit", "4.13.2"),
+		dep("com.google.firebase", "firebase-core", "21.1.1"),
+		dep("com.google.firebase.crashlytics", "crashlytics", "19.0.0"),
+		dep("org.jetbrains.kotlin", "kotlin-stdlib-jdk7", "1.9.0"),
+		dep("org.jetbrains.kotlin", "kotlin-stdlib", "1.9.0"),
+		dep("androidx.core", "core", "1.13.0"),
+	)
+
+	actual := violated(Check(cfg, r))
+
+	expected := map[string]string{
+		"com.android.support:appcompat-v7:28.0.0":       "deny",
+		"junit:junit:4.13.2":                            "deny",
+		"com.google.firebase:firebase-core:21.1.1":      "deny",
+		"org.jetbrains.kotlin:kotlin-stdlib-jdk7:1.9.0": "deny",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected violations %v, got: %v", expected, actual)
+	}
+}
+
+func TestCheck_DenyMatchedBySeveralPatterns(t *testing.T) {
+	cfg := config.Config{
+		Policy: config.PolicyConfig{Deny: []string{"junit", "junit:*", "junit:junit"}},
+	}
+
+	violations := Check(cfg, reportWith(dep("junit", "junit", "4.13.2")))
+
+	if len(violations) != 1 || violations[0].Message != "junit:junit:4.13.2 is denied by `junit`" {
+		t.Errorf("Expected single violation of the first pattern, got: %v", violations)
+	}
+}
+
+func TestCheck_DenyUnstable(t *testing.T) {
+	r := reportWith(
+		dep("androidx.compose.ui", "ui", "1.8.0-alpha01"),
+		dep("com.squareup.okhttp3", "okhttp", "5.0.0-alpha.14"),
+		dep("com.example", "snapshot", "1.0-SNAPSHOT"),
+		dep("androidx.core", "core", "1.13.0"),
+	)
+
+	if violations := Check(config.Config{}, r); len(violations) != 0 {
+		t.Errorf("Expected no violations unless unstable dependencies are denied, got: %v", violations)
+	}
+
+	cfg := config.Config{
+		Policy: config.PolicyConfig{
+			DenyUnstable:  true,
+			AllowUnstable: []string{"androidx.compose.*"},
+		},
+	}
+	actual := violated(Check(cfg, r))
+
+	expected := map[string]string{
+		"com.squareup.okhttp3:okhttp:5.0.0-alpha.14": "deny-unstable",
+		"com.example:snapshot:1.0-SNAPSHOT":          "deny-unstable",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected violations %v, got: %v", expected, actual)
+	}
+}
+
+func TestCheck_AllowUnstableDoesNotOverrideDeny(t *testing.T) {
+	cfg := config.Config{
+		Policy: config.PolicyConfig{
+			Deny:          []string{"com.squareup.okhttp3:okhttp"},
+			DenyUnstable:  true,
+			AllowUnstable: []string{"com.squareup.okhttp3"},
+		},
+	}
+
+	violations := Check(cfg, reportWith(dep("com.squareup.okhttp3", "okhttp", "5.0.0-alpha.14")))
+
+	if len(violations) != 1 || violations[0].Rule != "deny" {
+		t.Errorf("Expected only deny violation, got: %v", violations)
+	}
+}
+
+func TestCheck_IgnoredDependencies(t *testing.T) {
+	cfg := config.Config{
+		Ignore: []string{"com.example.internal", "junit:junit"},
+		Policy: config.PolicyConfig{
+			Deny:         []string{"junit:*", "com.example.*"},
+			DenyUnstable: true,
+		},
+	}
+	r := reportWith(
+		dep("junit", "junit", "4.13.2"),
+		dep("junit", "junit-dep", "4.11"),
+		dep("com.example.internal", "tools", "1.0-SNAPSHOT"),
+		dep("com.example.public", "api", "1.0"),
+	)
+
+	actual := violated(Check(cfg, r))
+
+	expected := map[string]string{
+		"junit:junit-dep:4.11":       "deny",
+		"com.example.public:api:1.0": "deny",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected violations %v, got: %v", expected, actual)
+	}
+}
@@ -0,0 +1,44 @@
+package proc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// Time given to the process (and its children) to exit after cancellation before it is killed.
+const WaitDelay = 10 * time.Second
+
+// Command creates command that is stopped together with its child processes
+// when context is cancelled or its deadline is exceeded.
+func Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	configureProcessGroup(cmd)
+	cmd.WaitDelay = WaitDelay
+	return cmd
+}
+
+// WithTimeout returns context with timeout (no timeout if it is not positive).
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Wrap explains failure of a step if it was caused by timeout or cancellation.
+// Otherwise `err` is returned as is.
+func Wrap(ctx context.Context, step string, timeout time.Duration, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%s timed out after %s", step, timeout)
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%s was cancelled", step)
+	}
+	return err
+}
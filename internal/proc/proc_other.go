@@ -0,0 +1,11 @@
+//go:build !unix
+
+package proc
+
+import (
+	"os/exec"
+)
+
+// configureProcessGroup keeps default behaviour (only the process itself is killed).
+func configureProcessGroup(cmd *exec.Cmd) {
+}
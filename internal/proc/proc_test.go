@@ -0,0 +1,48 @@
+package proc
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell is not available")
+	}
+
+	timeout := 200 * time.Millisecond
+	ctx, cancel := WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Child process of the shell must be stopped too (otherwise Wait would block on its output)
+	start := time.Now()
+	cmd := Command(ctx, "sh", "-c", "sleep 30 & wait")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected command to fail, got output: %s", output)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected command to be stopped on timeout, took %s", elapsed)
+	}
+
+	err = Wrap(ctx, "build", timeout, err)
+	if !strings.Contains(err.Error(), "build timed out after 200ms") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := Wrap(ctx, "build", 0, nil); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	cancel()
+	err := Wrap(ctx, "build", 0, context.Canceled)
+	if err == nil || err.Error() != "build was cancelled" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
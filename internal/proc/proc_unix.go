@@ -0,0 +1,18 @@
+//go:build unix
+
+package proc
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// configureProcessGroup starts the command in its own process group,
+// so cancellation also stops processes it has spawned (e.g. JVM started by `gradlew`).
+func configureProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		// Negative pid addresses the whole process group
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+	}
+}
@@ -0,0 +1,53 @@
+package progress
+
+import (
+	"strings"
+)
+
+// FailureSummary returns the most relevant lines of failed Gradle build output:
+// compiler errors and "What went wrong" section (or the last lines if there are none).
+func FailureSummary(output string, max int) []string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	result := []string{}
+	for _, line := range lines {
+		if isErrorLine(line) {
+			result = append(result, line)
+		}
+	}
+
+	inFailure := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "* What went wrong:":
+			inFailure = true
+		case strings.HasPrefix(trimmed, "* Try:"), strings.HasPrefix(trimmed, "* Get more help"), strings.HasPrefix(trimmed, "BUILD FAILED"):
+			inFailure = false
+			continue
+		}
+		if inFailure && trimmed != "" {
+			result = append(result, line)
+		}
+	}
+
+	if len(result) == 0 {
+		for _, line := range lines {
+			if strings.TrimSpace(line) != "" {
+				result = append(result, line)
+			}
+		}
+	}
+
+	if len(result) > max {
+		result = result[len(result)-max:]
+	}
+	return result
+}
+
+func isErrorLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "e: ") ||
+		strings.HasPrefix(trimmed, "ERROR:") ||
+		strings.Contains(trimmed, ": error:")
+}
@@ -0,0 +1,118 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"lampa/internal/utils"
+)
+
+const (
+	FormatText = "text"
+	FormatJson = "json"
+)
+
+var Formats = []string{FormatText, FormatJson}
+
+// Entry is a single line of build output in JSON log format.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// Seconds since the log was opened
+	Elapsed float64 `json:"elapsed"`
+	// Task that was running when the line was printed
+	Task    string `json:"task,omitempty"`
+	Message string `json:"message"`
+}
+
+// Log writes build output to a file (and optionally to console) and tracks the current Gradle task.
+type Log struct {
+	mu sync.Mutex
+
+	Path    string
+	format  string
+	file    *os.File
+	console io.Writer
+
+	start time.Time
+	task  string
+}
+
+// Open creates log file at `path`. Output is duplicated to `console` if it is not nil.
+func Open(path string, format string, console io.Writer) (*Log, error) {
+	if err := utils.EnsureParentDirExists(path); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create log file `%s`: %v", path, err)
+	}
+
+	return &Log{
+		Path:    path,
+		format:  format,
+		file:    file,
+		console: console,
+		start:   time.Now(),
+	}, nil
+}
+
+// Write records the line of output. Returns task name if the line starts a new Gradle task.
+func (self *Log) Write(line string) (string, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	task, isTask := ParseTaskHeader(line)
+	if isTask {
+		self.task = task
+	}
+
+	formatted := line
+	if self.format == FormatJson {
+		now := time.Now()
+		data := strings.Builder{}
+		encoder := json.NewEncoder(&data)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(Entry{
+			Time:    now.UTC(),
+			Elapsed: now.Sub(self.start).Round(time.Millisecond).Seconds(),
+			Task:    self.task,
+			Message: line,
+		})
+		formatted = strings.TrimSuffix(data.String(), "\n")
+	}
+
+	fmt.Fprintln(self.file, formatted)
+	if self.console != nil {
+		fmt.Fprintln(self.console, formatted)
+	}
+
+	return task, isTask
+}
+
+func (self *Log) Close() error {
+	return self.file.Close()
+}
+
+// ParseTaskHeader returns task name if the line is a task header ("> Task :app:bundleRelease").
+func ParseTaskHeader(line string) (string, bool) {
+	name, ok := strings.CutPrefix(strings.TrimSpace(line), "> Task ")
+	if !ok {
+		return "", false
+	}
+	// Outcome might follow the name (e.g. "UP-TO-DATE")
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return "", false
+	}
+	return fields[0], true
+}
+
+// FormatElapsed returns human-readable duration (e.g. "1m5s").
+func FormatElapsed(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
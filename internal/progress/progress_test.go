@@ -0,0 +1,83 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLog_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build", "report.log")
+	console := &bytes.Buffer{}
+	log, err := Open(path, FormatJson, console)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	if _, ok := log.Write("Starting a Gradle Daemon"); ok {
+		t.Errorf("Expected regular line not to start a task")
+	}
+	task, ok := log.Write("> Task :app:compileReleaseKotlin UP-TO-DATE")
+	if !ok || task != ":app:compileReleaseKotlin" {
+		t.Errorf("Unexpected task: %q", task)
+	}
+	log.Write("w: deprecated API")
+	log.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 log entries, got:\n%s", data)
+	}
+	entry := Entry{}
+	if err := json.Unmarshal([]byte(lines[2]), &entry); err != nil {
+		t.Fatalf("Could not parse log entry: %v", err)
+	}
+	if entry.Task != ":app:compileReleaseKotlin" || entry.Message != "w: deprecated API" {
+		t.Errorf("Unexpected log entry: %#v", entry)
+	}
+	if console.String() != string(data) {
+		t.Errorf("Expected console output to match log file, got:\n%s", console.String())
+	}
+}
+
+func TestFailureSummary(t *testing.T) {
+	output := `> Task :app:preBuild UP-TO-DATE
+> Task :app:compileReleaseKotlin FAILED
+e: file:///app/src/main/java/Main.kt:10:5 Unresolved reference 'foo'.
+
+FAILURE: Build failed with an exception.
+
+* What went wrong:
+Execution failed for task ':app:compileReleaseKotlin'.
+> A failure occurred while executing org.jetbrains.kotlin.compilerRunner.GradleCompilerRunnerWithWorkers$GradleKotlinCompilerWorkAction
+   > Compilation error. See log for more details
+
+* Try:
+> Run with --stacktrace option to get the stack trace.
+
+BUILD FAILED in 12s
+`
+	summary := FailureSummary(output, 30)
+	expected := []string{
+		"e: file:///app/src/main/java/Main.kt:10:5 Unresolved reference 'foo'.",
+		"* What went wrong:",
+		"Execution failed for task ':app:compileReleaseKotlin'.",
+		"> A failure occurred while executing org.jetbrains.kotlin.compilerRunner.GradleCompilerRunnerWithWorkers$GradleKotlinCompilerWorkAction",
+		"   > Compilation error. See log for more details",
+	}
+	if strings.Join(summary, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected summary:\n%s", strings.Join(summary, "\n"))
+	}
+
+	summary = FailureSummary("line 1\nline 2\n\nline 3\n", 2)
+	if len(summary) != 2 || summary[0] != "line 2" || summary[1] != "line 3" {
+		t.Errorf("Expected last lines for unknown failure, got: %q", summary)
+	}
+}
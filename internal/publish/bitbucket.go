@@ -0,0 +1,82 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const BitbucketApiUrl = "https://api.bitbucket.org/2.0"
+
+// Bitbucket Cloud pull request comments API.
+type Bitbucket struct {
+	api       apiClient
+	base      string
+	workspace string
+	name      string
+}
+
+func NewBitbucket(opts Options) (*Bitbucket, error) {
+	workspace, name, err := splitRepo(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{}
+	if opts.Token != "" {
+		headers["Authorization"] = "Bearer " + opts.Token
+	}
+	return &Bitbucket{
+		api:       apiClient{client: opts.Client, headers: headers},
+		base:      apiUrl(opts, BitbucketApiUrl),
+		workspace: workspace,
+		name:      name,
+	}, nil
+}
+
+type bitbucketContent struct {
+	Raw string `json:"raw"`
+}
+
+type bitbucketComment struct {
+	Id      int64            `json:"id"`
+	Content bitbucketContent `json:"content"`
+	Deleted bool             `json:"deleted"`
+}
+
+type bitbucketPage struct {
+	Values []bitbucketComment `json:"values"`
+	Next   string             `json:"next"`
+}
+
+func (self *Bitbucket) commentsUrl(pr int) string {
+	return fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments", self.base, self.workspace, self.name, pr)
+}
+
+func (self *Bitbucket) Comments(ctx context.Context, pr int) ([]Comment, error) {
+	result := []Comment{}
+	url := self.commentsUrl(pr) + "?pagelen=100"
+	for page := 1; url != "" && page <= maxPages; page++ {
+		p := bitbucketPage{}
+		if err := self.api.do(ctx, http.MethodGet, url, nil, &p); err != nil {
+			return nil, err
+		}
+		for _, c := range p.Values {
+			if c.Deleted {
+				continue
+			}
+			result = append(result, Comment{Id: strconv.FormatInt(c.Id, 10), Body: c.Content.Raw})
+		}
+		url = p.Next
+	}
+	return result, nil
+}
+
+func (self *Bitbucket) CreateComment(ctx context.Context, pr int, body string) error {
+	return self.api.do(ctx, http.MethodPost, self.commentsUrl(pr), map[string]any{"content": bitbucketContent{Raw: body}}, nil)
+}
+
+func (self *Bitbucket) UpdateComment(ctx context.Context, pr int, id string, body string) error {
+	url := fmt.Sprintf("%s/%s", self.commentsUrl(pr), id)
+	return self.api.do(ctx, http.MethodPut, url, map[string]any{"content": bitbucketContent{Raw: body}}, nil)
+}
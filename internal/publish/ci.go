@@ -0,0 +1,94 @@
+package publish
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Target is a pull request to publish comment to.
+type Target struct {
+	Forge string
+	Options
+	PR int
+}
+
+// DetectCi returns pull request of the current CI job (GitHub Actions, GitLab CI,
+// Gitea Actions or Bitbucket Pipelines). Empty fields are left if it can't be detected.
+func DetectCi(getenv func(string) string) Target {
+	env := func(names ...string) string {
+		for _, name := range names {
+			if v := strings.TrimSpace(getenv(name)); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	switch {
+	// Gitea sets GitHub variables too, so it's checked first
+	case env("GITEA_ACTIONS") == "true":
+		apiUrl := ""
+		if server := env("GITHUB_SERVER_URL"); server != "" {
+			apiUrl = strings.TrimRight(server, "/") + "/api/v1"
+		}
+		return Target{
+			Forge:   ForgeGitea,
+			Options: Options{ApiUrl: apiUrl, Repo: env("GITHUB_REPOSITORY"), Token: env("GITEA_TOKEN", "GITHUB_TOKEN")},
+			PR:      pullRequestFromRef(env("GITHUB_REF")),
+		}
+	case env("GITHUB_ACTIONS") == "true":
+		return Target{
+			Forge:   ForgeGitHub,
+			Options: Options{ApiUrl: env("GITHUB_API_URL"), Repo: env("GITHUB_REPOSITORY"), Token: env("GITHUB_TOKEN")},
+			PR:      pullRequestFromRef(env("GITHUB_REF")),
+		}
+	case env("GITLAB_CI") == "true":
+		iid, _ := strconv.Atoi(env("CI_MERGE_REQUEST_IID"))
+		return Target{
+			Forge:   ForgeGitLab,
+			Options: Options{ApiUrl: env("CI_API_V4_URL"), Repo: env("CI_PROJECT_ID"), Token: env("GITLAB_TOKEN")},
+			PR:      iid,
+		}
+	case env("BITBUCKET_BUILD_NUMBER") != "":
+		id, _ := strconv.Atoi(env("BITBUCKET_PR_ID"))
+		return Target{
+			Forge:   ForgeBitbucket,
+			Options: Options{Repo: env("BITBUCKET_REPO_FULL_NAME"), Token: env("BITBUCKET_TOKEN")},
+			PR:      id,
+		}
+	}
+	return Target{}
+}
+
+// Resolve overrides detected target with explicitly set values (flags or config).
+// Detected values are discarded completely if another forge is chosen explicitly,
+// so CI credentials are never sent to a different host.
+func Resolve(detected Target, explicit Target) Target {
+	result := detected
+	if explicit.Forge != "" && !strings.EqualFold(explicit.Forge, detected.Forge) {
+		result = Target{Forge: explicit.Forge}
+	}
+	if explicit.ApiUrl != "" {
+		result.ApiUrl = explicit.ApiUrl
+	}
+	if explicit.Repo != "" {
+		result.Repo = explicit.Repo
+	}
+	if explicit.Token != "" {
+		result.Token = explicit.Token
+	}
+	if explicit.PR > 0 {
+		result.PR = explicit.PR
+	}
+	return result
+}
+
+// pullRequestFromRef parses number from "refs/pull/123/merge".
+func pullRequestFromRef(ref string) int {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "refs" || parts[1] != "pull" {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[2])
+	return n
+}
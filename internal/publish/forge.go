@@ -0,0 +1,178 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	ForgeGitHub    = "github"
+	ForgeGitLab    = "gitlab"
+	ForgeGitea     = "gitea"
+	ForgeBitbucket = "bitbucket"
+)
+
+var Forges = []string{ForgeGitHub, ForgeGitLab, ForgeGitea, ForgeBitbucket}
+
+// Marker identifies comment posted by lampa (it's updated instead of posting a new one).
+const Marker = "<!-- lampa:summary -->"
+
+type Comment struct {
+	Id   string
+	Body string
+}
+
+// Forge is a pull (merge) request comments API of the Git hosting.
+type Forge interface {
+	Comments(ctx context.Context, pr int) ([]Comment, error)
+	CreateComment(ctx context.Context, pr int, body string) error
+	UpdateComment(ctx context.Context, pr int, id string, body string) error
+}
+
+type Options struct {
+	// API base URL (default one of the public instance is used if empty)
+	ApiUrl string
+	Token  string
+	// "owner/name" (GitHub, Gitea), "workspace/repo" (Bitbucket) or project id/path (GitLab)
+	Repo string
+
+	Client *http.Client
+}
+
+// New creates client for the forge by name.
+func New(name string, opts Options) (Forge, error) {
+	if opts.Repo == "" {
+		return nil, fmt.Errorf("repository is not set")
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+
+	switch strings.ToLower(name) {
+	case ForgeGitHub:
+		return NewGitHub(opts)
+	case ForgeGitLab:
+		return NewGitLab(opts)
+	case ForgeGitea:
+		return NewGitea(opts)
+	case ForgeBitbucket:
+		return NewBitbucket(opts)
+	default:
+		return nil, fmt.Errorf("unknown forge %q (supported: %s)", name, strings.Join(Forges, ", "))
+	}
+}
+
+// Result of publishing the comment.
+type Result string
+
+const (
+	Created   Result = "created"
+	Updated   Result = "updated"
+	Unchanged Result = "unchanged"
+)
+
+// Publish creates sticky comment or updates the existing one (if its body differs).
+func Publish(ctx context.Context, forge Forge, pr int, body string) (Result, error) {
+	if !strings.Contains(body, Marker) {
+		body = Marker + "\n" + body
+	}
+
+	comments, err := forge.Comments(ctx, pr)
+	if err != nil {
+		return "", fmt.Errorf("could not list comments: %v", err)
+	}
+	idx := slices.IndexFunc(comments, func(c Comment) bool {
+		return strings.Contains(c.Body, Marker)
+	})
+	if idx < 0 {
+		if err := forge.CreateComment(ctx, pr, body); err != nil {
+			return "", fmt.Errorf("could not create comment: %v", err)
+		}
+		return Created, nil
+	}
+
+	if comments[idx].Body == body {
+		return Unchanged, nil
+	}
+	if err := forge.UpdateComment(ctx, pr, comments[idx].Id, body); err != nil {
+		return "", fmt.Errorf("could not update comment: %v", err)
+	}
+	return Updated, nil
+}
+
+func splitRepo(repo string) (string, string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("repository must be in `owner/name` format: %q", repo)
+	}
+	return owner, name, nil
+}
+
+// Max page count to look through (comment is usually found on the first one).
+const maxPages = 50
+
+type apiClient struct {
+	client  *http.Client
+	headers map[string]string
+}
+
+func (self apiClient) do(ctx context.Context, method string, url string, body any, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range self.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200] + "..."
+		}
+		return fmt.Errorf("%s %s: %s %s", method, url, resp.Status, msg)
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("%s %s: %v", method, url, err)
+	}
+	return nil
+}
+
+func apiUrl(opts Options, fallback string) string {
+	if opts.ApiUrl == "" {
+		return fallback
+	}
+	return strings.TrimRight(opts.ApiUrl, "/")
+}
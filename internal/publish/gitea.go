@@ -0,0 +1,73 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const GiteaApiUrl = "https://gitea.com/api/v1"
+
+// Gitea (and Forgejo) use issue comments API for pull requests.
+type Gitea struct {
+	api   apiClient
+	base  string
+	owner string
+	name  string
+}
+
+func NewGitea(opts Options) (*Gitea, error) {
+	owner, name, err := splitRepo(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{}
+	if opts.Token != "" {
+		headers["Authorization"] = "token " + opts.Token
+	}
+	return &Gitea{
+		api:   apiClient{client: opts.Client, headers: headers},
+		base:  apiUrl(opts, GiteaApiUrl),
+		owner: owner,
+		name:  name,
+	}, nil
+}
+
+type giteaComment struct {
+	Id   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+func (self *Gitea) repoUrl() string {
+	return fmt.Sprintf("%s/repos/%s/%s", self.base, self.owner, self.name)
+}
+
+func (self *Gitea) Comments(ctx context.Context, pr int) ([]Comment, error) {
+	const limit = 50
+	result := []Comment{}
+	for page := 1; page <= maxPages; page++ {
+		url := fmt.Sprintf("%s/issues/%d/comments?limit=%d&page=%d", self.repoUrl(), pr, limit, page)
+		comments := []giteaComment{}
+		if err := self.api.do(ctx, http.MethodGet, url, nil, &comments); err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			result = append(result, Comment{Id: strconv.FormatInt(c.Id, 10), Body: c.Body})
+		}
+		if len(comments) < limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (self *Gitea) CreateComment(ctx context.Context, pr int, body string) error {
+	url := fmt.Sprintf("%s/issues/%d/comments", self.repoUrl(), pr)
+	return self.api.do(ctx, http.MethodPost, url, map[string]string{"body": body}, nil)
+}
+
+func (self *Gitea) UpdateComment(ctx context.Context, pr int, id string, body string) error {
+	url := fmt.Sprintf("%s/issues/comments/%s", self.repoUrl(), id)
+	return self.api.do(ctx, http.MethodPatch, url, map[string]string{"body": body}, nil)
+}
@@ -0,0 +1,76 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const GitHubApiUrl = "https://api.github.com"
+
+// GitHub uses issue comments API (pull requests are issues there).
+type GitHub struct {
+	api   apiClient
+	base  string
+	owner string
+	name  string
+}
+
+func NewGitHub(opts Options) (*GitHub, error) {
+	owner, name, err := splitRepo(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	if opts.Token != "" {
+		headers["Authorization"] = "Bearer " + opts.Token
+	}
+	return &GitHub{
+		api:   apiClient{client: opts.Client, headers: headers},
+		base:  apiUrl(opts, GitHubApiUrl),
+		owner: owner,
+		name:  name,
+	}, nil
+}
+
+type githubComment struct {
+	Id   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+func (self *GitHub) repoUrl() string {
+	return fmt.Sprintf("%s/repos/%s/%s", self.base, self.owner, self.name)
+}
+
+func (self *GitHub) Comments(ctx context.Context, pr int) ([]Comment, error) {
+	const perPage = 100
+	result := []Comment{}
+	for page := 1; page <= maxPages; page++ {
+		url := fmt.Sprintf("%s/issues/%d/comments?per_page=%d&page=%d", self.repoUrl(), pr, perPage, page)
+		comments := []githubComment{}
+		if err := self.api.do(ctx, http.MethodGet, url, nil, &comments); err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			result = append(result, Comment{Id: strconv.FormatInt(c.Id, 10), Body: c.Body})
+		}
+		if len(comments) < perPage {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (self *GitHub) CreateComment(ctx context.Context, pr int, body string) error {
+	url := fmt.Sprintf("%s/issues/%d/comments", self.repoUrl(), pr)
+	return self.api.do(ctx, http.MethodPost, url, map[string]string{"body": body}, nil)
+}
+
+func (self *GitHub) UpdateComment(ctx context.Context, pr int, id string, body string) error {
+	url := fmt.Sprintf("%s/issues/comments/%s", self.repoUrl(), id)
+	return self.api.do(ctx, http.MethodPatch, url, map[string]string{"body": body}, nil)
+}
@@ -0,0 +1,71 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const GitLabApiUrl = "https://gitlab.com/api/v4"
+
+// GitLab uses merge request notes API.
+type GitLab struct {
+	api     apiClient
+	base    string
+	project string
+}
+
+func NewGitLab(opts Options) (*GitLab, error) {
+	headers := map[string]string{}
+	if opts.Token != "" {
+		headers["PRIVATE-TOKEN"] = opts.Token
+	}
+	return &GitLab{
+		api:     apiClient{client: opts.Client, headers: headers},
+		base:    apiUrl(opts, GitLabApiUrl),
+		project: opts.Repo,
+	}, nil
+}
+
+type gitlabNote struct {
+	Id     int64  `json:"id"`
+	Body   string `json:"body"`
+	System bool   `json:"system"`
+}
+
+func (self *GitLab) notesUrl(mr int) string {
+	return fmt.Sprintf("%s/projects/%s/merge_requests/%d/notes", self.base, url.PathEscape(self.project), mr)
+}
+
+func (self *GitLab) Comments(ctx context.Context, mr int) ([]Comment, error) {
+	const perPage = 100
+	result := []Comment{}
+	for page := 1; page <= maxPages; page++ {
+		url := fmt.Sprintf("%s?per_page=%d&page=%d", self.notesUrl(mr), perPage, page)
+		notes := []gitlabNote{}
+		if err := self.api.do(ctx, http.MethodGet, url, nil, &notes); err != nil {
+			return nil, err
+		}
+		for _, n := range notes {
+			if n.System {
+				continue
+			}
+			result = append(result, Comment{Id: strconv.FormatInt(n.Id, 10), Body: n.Body})
+		}
+		if len(notes) < perPage {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (self *GitLab) CreateComment(ctx context.Context, mr int, body string) error {
+	return self.api.do(ctx, http.MethodPost, self.notesUrl(mr), map[string]string{"body": body}, nil)
+}
+
+func (self *GitLab) UpdateComment(ctx context.Context, mr int, id string, body string) error {
+	url := fmt.Sprintf("%s/%s", self.notesUrl(mr), id)
+	return self.api.do(ctx, http.MethodPut, url, map[string]string{"body": body}, nil)
+}
@@ -0,0 +1,127 @@
+package publish
+
+import (
+	"fmt"
+	"lampa/internal/diff"
+	"lampa/internal/report"
+	"lampa/internal/templates"
+	"lampa/internal/templates/html/compare"
+	"strings"
+)
+
+// Markdown renders comparison summary for pull request comment.
+// Link to the full HTML report is added if `htmlUrl` is not empty.
+func Markdown(r1 *report.Report, r2 *report.Report, c diff.Comparison, htmlUrl string) string {
+	labels := compare.NewLabels(r1, r2, c.Mode)
+	b := &strings.Builder{}
+
+	fmt.Fprintln(b, Marker)
+	if c.Mode == diff.ModeVariants {
+		fmt.Fprintf(b, "### Lampa: %s...%s\n\n", r1.Build.BuildVariant, r2.Build.BuildVariant)
+	} else {
+		fmt.Fprintf(b, "### Lampa: %s...%s\n\n", versionOf(r1), versionOf(r2))
+	}
+
+	fmt.Fprintln(b, "| | Before | After |")
+	fmt.Fprintln(b, "|---|---|---|")
+	writeRow(b, "Size", templates.FormatFileSize(r1.Build.AabSize), templates.FormatFileSize(r2.Build.AabSize))
+	writeRow(b, "Min SDK", r1.Build.MinSdkVersion, r2.Build.MinSdkVersion)
+	writeRow(b, "Target SDK", r1.Build.TargetSdkVersion, r2.Build.TargetSdkVersion)
+	writeRow(b, "Compile SDK", r1.Build.CompileSdkVersion, r2.Build.CompileSdkVersion)
+	writeRow(b, "Dependencies",
+		fmt.Sprint(len(r1.Build.Dependencies.Compile)), fmt.Sprint(len(r2.Build.Dependencies.Compile)))
+	fmt.Fprintln(b)
+
+	deps := c.Dependencies
+	writeDeps(b, labels.New, deps.New)
+	writeDeps(b, labels.Removed, deps.Removed)
+	writeDeps(b, labels.Upgraded, deps.Upgraded)
+	writeDeps(b, labels.Downgraded, deps.Downgraded)
+	writeDeps(b, labels.Changed, deps.Changed)
+
+	if !c.Permissions.IsEmpty() {
+		fmt.Fprintln(b, "**Permissions**")
+		fmt.Fprintln(b)
+		for _, p := range c.Permissions.Added {
+			fmt.Fprintf(b, "- :warning: added `%s`\n", p)
+		}
+		for _, p := range c.Permissions.Removed {
+			fmt.Fprintf(b, "- removed `%s`\n", p)
+		}
+		fmt.Fprintln(b)
+	}
+
+	if len(c.Catalog) > 0 {
+		fmt.Fprintf(b, "<details><summary>Version catalog (%d)</summary>\n\n", len(c.Catalog))
+		for _, it := range c.Catalog {
+			mark := "~"
+			if it.IsAdded() {
+				mark = "+"
+			} else if it.IsRemoved() {
+				mark = "-"
+			}
+			fmt.Fprintf(b, "- %s `%s` %s\n", mark, it.Accessor, it.Summary())
+		}
+		fmt.Fprint(b, "\n</details>\n\n")
+	}
+
+	findings := []string{}
+	if n := len(c.Conflicts); n > 0 {
+		findings = append(findings, fmt.Sprintf("Version conflicts: %d", n))
+	}
+	if n := len(c.Unstable); n > 0 {
+		findings = append(findings, fmt.Sprintf("Unstable dependencies: %d", n))
+	}
+	if n := len(c.ChecksumChanges); n > 0 {
+		findings = append(findings, fmt.Sprintf("Same version, different bytes: %d", n))
+	}
+	if n := len(c.RepositoryChanges) + len(c.NewRepositories); n > 0 {
+		findings = append(findings, fmt.Sprintf("Repository changes: %d", n))
+	}
+	for _, f := range findings {
+		fmt.Fprintf(b, "- :warning: %s\n", f)
+	}
+	if len(findings) > 0 {
+		fmt.Fprintln(b)
+	}
+
+	if htmlUrl != "" {
+		fmt.Fprintf(b, "[Full report](%s)\n", htmlUrl)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func versionOf(r *report.Report) string {
+	if r.Build.VersionCode == "" {
+		return r.Build.VersionName
+	}
+	return fmt.Sprintf("%s (%s)", r.Build.VersionName, r.Build.VersionCode)
+}
+
+func writeRow(b *strings.Builder, name string, before string, after string) {
+	if before == "" && after == "" {
+		return
+	}
+	if before != after {
+		after = "**" + after + "**"
+	}
+	fmt.Fprintf(b, "| %s | %s | %s |\n", name, before, after)
+}
+
+func writeDeps(b *strings.Builder, label string, deps []diff.Dep) {
+	if len(deps) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "<details><summary>%s (%d)</summary>\n\n", label, len(deps))
+	for _, d := range deps {
+		fmt.Fprintf(b, "- `%s` %s", d.Name(), d.Version)
+		if d.Alias != "" {
+			fmt.Fprintf(b, " (`%s`)", d.Alias)
+		}
+		for _, link := range d.Links {
+			fmt.Fprintf(b, " ([%s](%s))", link.Title, link.Url)
+		}
+		fmt.Fprintln(b)
+	}
+	fmt.Fprint(b, "\n</details>\n\n")
+}
@@ -0,0 +1,389 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"lampa/internal/diff"
+	"lampa/internal/report"
+)
+
+// fakeForge keeps comments of a single pull request.
+type fakeForge struct {
+	mu       sync.Mutex
+	comments []fakeComment
+	auth     []string
+}
+
+type fakeComment struct {
+	Id   int64
+	Body string
+}
+
+func (self *fakeForge) list() []fakeComment {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return append([]fakeComment{}, self.comments...)
+}
+
+func (self *fakeForge) add(body string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.comments = append(self.comments, fakeComment{Id: int64(len(self.comments) + 1), Body: body})
+}
+
+func (self *fakeForge) update(id string, body string) bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	n, _ := strconv.ParseInt(id, 10, 64)
+	for i := range self.comments {
+		if self.comments[i].Id == n {
+			self.comments[i].Body = body
+			return true
+		}
+	}
+	return false
+}
+
+func (self *fakeForge) record(r *http.Request, headers ...string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	for _, h := range headers {
+		if v := r.Header.Get(h); v != "" {
+			self.auth = append(self.auth, v)
+		}
+	}
+}
+
+func decodeBody(r *http.Request) string {
+	payload := struct {
+		Body    string `json:"body"`
+		Content struct {
+			Raw string `json:"raw"`
+		} `json:"content"`
+	}{}
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+	if payload.Content.Raw != "" {
+		return payload.Content.Raw
+	}
+	return payload.Body
+}
+
+// handler serves issue comments API in GitHub/Gitea style.
+func (self *fakeForge) issuesHandler(prefix string, authHeader string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+prefix+"/repos/acme/app/issues/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		self.record(r, authHeader)
+		result := []map[string]any{}
+		if r.URL.Query().Get("page") == "1" {
+			for _, c := range self.list() {
+				result = append(result, map[string]any{"id": c.Id, "body": c.Body})
+			}
+		}
+		json.NewEncoder(w).Encode(result)
+	})
+	mux.HandleFunc("POST "+prefix+"/repos/acme/app/issues/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		self.record(r, authHeader)
+		self.add(decodeBody(r))
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("PATCH "+prefix+"/repos/acme/app/issues/comments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		self.record(r, authHeader)
+		if !self.update(r.PathValue("id"), decodeBody(r)) {
+			http.NotFound(w, r)
+		}
+	})
+	return mux
+}
+
+func (self *fakeForge) gitlabHandler() http.Handler {
+	mux := http.NewServeMux()
+	notes := "/api/v4/projects/{project}/merge_requests/7/notes"
+	checkProject := func(w http.ResponseWriter, r *http.Request) bool {
+		self.record(r, "PRIVATE-TOKEN")
+		if r.PathValue("project") != "acme/app" {
+			http.NotFound(w, r)
+			return false
+		}
+		return true
+	}
+	mux.HandleFunc("GET "+notes, func(w http.ResponseWriter, r *http.Request) {
+		if !checkProject(w, r) {
+			return
+		}
+		result := []map[string]any{{"id": 100, "body": "merged", "system": true}}
+		for _, c := range self.list() {
+			result = append(result, map[string]any{"id": c.Id, "body": c.Body})
+		}
+		json.NewEncoder(w).Encode(result)
+	})
+	mux.HandleFunc("POST "+notes, func(w http.ResponseWriter, r *http.Request) {
+		if checkProject(w, r) {
+			self.add(decodeBody(r))
+		}
+	})
+	mux.HandleFunc("PUT "+notes+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if checkProject(w, r) && !self.update(r.PathValue("id"), decodeBody(r)) {
+			http.NotFound(w, r)
+		}
+	})
+	return mux
+}
+
+func (self *fakeForge) bitbucketHandler(srv **httptest.Server) http.Handler {
+	mux := http.NewServeMux()
+	comments := "/2.0/repositories/acme/app/pullrequests/7/comments"
+	mux.HandleFunc("GET "+comments, func(w http.ResponseWriter, r *http.Request) {
+		self.record(r, "Authorization")
+		// Every comment on its own page to check pagination
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		list := self.list()
+		result := map[string]any{"values": []any{}}
+		if page < len(list) {
+			c := list[page]
+			result["values"] = []any{map[string]any{"id": c.Id, "content": map[string]string{"raw": c.Body}}}
+		}
+		if page+1 < len(list) {
+			result["next"] = fmt.Sprintf("%s%s?page=%d", (*srv).URL, comments, page+1)
+		}
+		json.NewEncoder(w).Encode(result)
+	})
+	mux.HandleFunc("POST "+comments, func(w http.ResponseWriter, r *http.Request) {
+		self.record(r, "Authorization")
+		self.add(decodeBody(r))
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("PUT "+comments+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		self.record(r, "Authorization")
+		if !self.update(r.PathValue("id"), decodeBody(r)) {
+			http.NotFound(w, r)
+		}
+	})
+	return mux
+}
+
+func TestPublish(t *testing.T) {
+	cases := []struct {
+		forge   string
+		apiPath string
+		auth    string
+		handler func(f *fakeForge, srv **httptest.Server) http.Handler
+	}{
+		{ForgeGitHub, "/api/v3", "Bearer secret", func(f *fakeForge, _ **httptest.Server) http.Handler {
+			return f.issuesHandler("/api/v3", "Authorization")
+		}},
+		{ForgeGitea, "/api/v1", "token secret", func(f *fakeForge, _ **httptest.Server) http.Handler {
+			return f.issuesHandler("/api/v1", "Authorization")
+		}},
+		{ForgeGitLab, "/api/v4", "secret", func(f *fakeForge, _ **httptest.Server) http.Handler {
+			return f.gitlabHandler()
+		}},
+		{ForgeBitbucket, "/2.0", "Bearer secret", func(f *fakeForge, srv **httptest.Server) http.Handler {
+			return f.bitbucketHandler(srv)
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.forge, func(t *testing.T) {
+			fake := &fakeForge{}
+			fake.add("LGTM")
+			var srv *httptest.Server
+			srv = httptest.NewServer(tc.handler(fake, &srv))
+			defer srv.Close()
+
+			forge, err := New(tc.forge, Options{ApiUrl: srv.URL + tc.apiPath + "/", Token: "secret", Repo: "acme/app"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+
+			result, err := Publish(ctx, forge, 7, "first")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != Created {
+				t.Errorf("comment was not created: %s", result)
+			}
+
+			result, err = Publish(ctx, forge, 7, Marker+"\nsecond")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != Updated {
+				t.Errorf("comment was not updated: %s", result)
+			}
+
+			result, err = Publish(ctx, forge, 7, Marker+"\nsecond")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != Unchanged {
+				t.Errorf("identical comment was not left unchanged: %s", result)
+			}
+
+			comments := fake.list()
+			if len(comments) != 2 {
+				t.Fatalf("comments: %v", comments)
+			}
+			if comments[0].Body != "LGTM" || comments[1].Body != Marker+"\nsecond" {
+				t.Errorf("comments: %v", comments)
+			}
+			for _, auth := range fake.auth {
+				if auth != tc.auth {
+					t.Errorf("auth header: %q", auth)
+				}
+			}
+		})
+	}
+}
+
+func TestPublishError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	forge, err := New(ForgeGitHub, Options{ApiUrl: srv.URL, Repo: "acme/app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Publish(context.Background(), forge, 1, "body")
+	if err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("error: %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("svn", Options{Repo: "acme/app"}); err == nil {
+		t.Errorf("unknown forge accepted")
+	}
+	if _, err := New(ForgeGitHub, Options{Repo: "app"}); err == nil {
+		t.Errorf("repository without owner accepted")
+	}
+	if _, err := New(ForgeGitLab, Options{Repo: "group/subgroup/app"}); err != nil {
+		t.Errorf("nested GitLab project rejected: %v", err)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	r1 := &report.Report{}
+	r1.Build.VersionName = "1.0"
+	r1.Build.VersionCode = "1"
+	r1.Build.MinSdkVersion = "24"
+	r2 := &report.Report{}
+	r2.Build.VersionName = "1.1"
+	r2.Build.VersionCode = "2"
+	r2.Build.MinSdkVersion = "26"
+
+	c := diff.Comparison{
+		Dependencies: diff.DependenciesDiff{
+			Upgraded: []diff.Dep{{Coordinate: "a:b", Version: "1.0 → 1.1"}},
+		},
+		Permissions: diff.PermissionsDiff{Added: []string{"android.permission.CAMERA"}},
+	}
+	md := Markdown(r1, r2, c, "https://ci.example.com/diff.html")
+
+	for _, s := range []string{
+		Marker,
+		"### Lampa: 1.0 (1)...1.1 (2)",
+		"| Min SDK | 24 | **26** |",
+		"<details><summary>Upgraded (1)</summary>",
+		"- `a:b` 1.0 → 1.1",
+		"added `android.permission.CAMERA`",
+		"[Full report](https://ci.example.com/diff.html)",
+	} {
+		if !strings.Contains(md, s) {
+			t.Errorf("%q not found in:\n%s", s, md)
+		}
+	}
+	if strings.Contains(md, "Removed") {
+		t.Errorf("empty category rendered:\n%s", md)
+	}
+}
+
+func TestDetectCi(t *testing.T) {
+	cases := []struct {
+		env  map[string]string
+		want Target
+	}{
+		{
+			env: map[string]string{
+				"GITHUB_ACTIONS": "true", "GITHUB_REPOSITORY": "acme/app", "GITHUB_TOKEN": "t",
+				"GITHUB_API_URL": "https://api.github.com", "GITHUB_REF": "refs/pull/12/merge",
+			},
+			want: Target{Forge: ForgeGitHub, Options: Options{ApiUrl: "https://api.github.com", Repo: "acme/app", Token: "t"}, PR: 12},
+		},
+		{
+			env: map[string]string{
+				"GITHUB_ACTIONS": "true", "GITEA_ACTIONS": "true", "GITHUB_SERVER_URL": "https://git.example.com/",
+				"GITHUB_REPOSITORY": "acme/app", "GITHUB_REF": "refs/heads/main",
+			},
+			want: Target{Forge: ForgeGitea, Options: Options{ApiUrl: "https://git.example.com/api/v1", Repo: "acme/app"}},
+		},
+		{
+			env: map[string]string{
+				"GITLAB_CI": "true", "CI_API_V4_URL": "https://gitlab.example.com/api/v4",
+				"CI_PROJECT_ID": "42", "CI_MERGE_REQUEST_IID": "3", "GITLAB_TOKEN": "t",
+			},
+			want: Target{Forge: ForgeGitLab, Options: Options{ApiUrl: "https://gitlab.example.com/api/v4", Repo: "42", Token: "t"}, PR: 3},
+		},
+		{
+			env:  map[string]string{"BITBUCKET_BUILD_NUMBER": "1", "BITBUCKET_REPO_FULL_NAME": "acme/app", "BITBUCKET_PR_ID": "5"},
+			want: Target{Forge: ForgeBitbucket, Options: Options{Repo: "acme/app"}, PR: 5},
+		},
+		{
+			env:  map[string]string{"CI": "true"},
+			want: Target{},
+		},
+	}
+
+	for _, tc := range cases {
+		got := DetectCi(func(name string) string { return tc.env[name] })
+		if got != tc.want {
+			t.Errorf("env %v:\ngot  %+v\nwant %+v", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	detected := Target{
+		Forge:   ForgeGitHub,
+		Options: Options{ApiUrl: "https://api.github.com", Repo: "acme/app", Token: "ci-token"},
+		PR:      12,
+	}
+
+	cases := []struct {
+		name     string
+		explicit Target
+		want     Target
+	}{
+		{"nothing set", Target{}, detected},
+		{
+			"same forge",
+			Target{Forge: "GitHub", Options: Options{Repo: "acme/other"}},
+			Target{Forge: ForgeGitHub, Options: Options{ApiUrl: "https://api.github.com", Repo: "acme/other", Token: "ci-token"}, PR: 12},
+		},
+		{
+			"another forge",
+			Target{Forge: ForgeGitea},
+			Target{Forge: ForgeGitea},
+		},
+		{
+			"another self-hosted forge",
+			Target{Forge: ForgeGitLab, Options: Options{ApiUrl: "https://gitlab.example.com/api/v4"}, PR: 3},
+			Target{Forge: ForgeGitLab, Options: Options{ApiUrl: "https://gitlab.example.com/api/v4"}, PR: 3},
+		},
+	}
+	for _, tc := range cases {
+		if got := Resolve(detected, tc.explicit); got != tc.want {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tc.name, got, tc.want)
+		}
+	}
+}
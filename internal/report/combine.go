@@ -0,0 +1,64 @@
+package report
+
+import (
+	"slices"
+	"strings"
+)
+
+// SortDependencies orders dependencies by group, name and version.
+func SortDependencies(deps []CoordinatedDependency) {
+	slices.SortFunc(deps, func(a, b CoordinatedDependency) int {
+		if c := strings.Compare(a.Group, b.Group); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Version, b.Version)
+	})
+}
+
+// Combine merges reports of several build variants (of the same commit) into one.
+// Build information (file, SDK versions, permissions) is taken from the first report and labeled with its variant,
+// dependencies are marked with variants they are used in (and versions of variants if they differ).
+func Combine(reports []*Report) Report {
+	if len(reports) == 0 {
+		return Report{}
+	}
+
+	result := *reports[0]
+	result.Build.Dependencies.Compile = nil
+
+	variants := []string{}
+	index := map[string]int{}
+	for _, r := range reports {
+		variant := r.Build.BuildVariant
+		variants = append(variants, variant)
+
+		for _, d := range r.Build.Dependencies.Compile {
+			if i, ok := index[d.Coordinate()]; ok {
+				combined := &result.Build.Dependencies.Compile[i]
+				if combined.Version != d.Version && combined.VariantVersions == nil {
+					combined.VariantVersions = map[string]string{}
+					for _, v := range combined.Variants {
+						combined.VariantVersions[v] = combined.Version
+					}
+				}
+				if combined.VariantVersions != nil {
+					combined.VariantVersions[variant] = d.Version
+				}
+				combined.Variants = append(combined.Variants, variant)
+				continue
+			}
+			d.Variants = []string{variant}
+			index[d.Coordinate()] = len(result.Build.Dependencies.Compile)
+			result.Build.Dependencies.Compile = append(result.Build.Dependencies.Compile, d)
+		}
+	}
+
+	result.Build.BuildVariant = strings.Join(variants, ", ")
+	result.Build.Variants = variants
+	result.Build.BuildDataVariant = variants[0]
+	SortDependencies(result.Build.Dependencies.Compile)
+	return result
+}
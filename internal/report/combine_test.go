@@ -0,0 +1,56 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCombine(t *testing.T) {
+	release := &Report{}
+	release.Build.BuildVariant = "prodRelease"
+	release.Build.Dependencies.Compile = []CoordinatedDependency{
+		{Group: "com.squareup.okhttp3", Name: "okhttp", Version: "4.12.0"},
+		{Group: "androidx.core", Name: "core", Version: "1.13.0"},
+	}
+	debug := &Report{}
+	debug.Build.BuildVariant = "demoDebug"
+	debug.Build.Dependencies.Compile = []CoordinatedDependency{
+		{Group: "androidx.core", Name: "core", Version: "1.13.0"},
+		{Group: "com.squareup.leakcanary", Name: "leakcanary-android", Version: "2.14"},
+		{Group: "com.squareup.okhttp3", Name: "okhttp", Version: "4.11.0"},
+	}
+
+	result := Combine([]*Report{release, debug})
+
+	if result.Build.BuildVariant != "prodRelease, demoDebug" {
+		t.Errorf("unexpected variant %q", result.Build.BuildVariant)
+	}
+	if result.Build.BuildDataVariant != "prodRelease" {
+		t.Errorf("build data should be labeled with the first variant, got %q", result.Build.BuildDataVariant)
+	}
+	actual := map[string][]string{}
+	for _, d := range result.Build.Dependencies.Compile {
+		actual[d.String()] = d.Variants
+	}
+	expected := map[string][]string{
+		"androidx.core:core:1.13.0":                       {"prodRelease", "demoDebug"},
+		"com.squareup.leakcanary:leakcanary-android:2.14": {"demoDebug"},
+		"com.squareup.okhttp3:okhttp:4.12.0":              {"prodRelease", "demoDebug"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected dependencies %v", actual)
+	}
+	okhttp := result.Build.Dependencies.Compile[2]
+	if expected := map[string]string{"prodRelease": "4.12.0", "demoDebug": "4.11.0"}; !reflect.DeepEqual(okhttp.VariantVersions, expected) {
+		t.Errorf("expected versions of variants %v, got %v", expected, okhttp.VariantVersions)
+	}
+	if core := result.Build.Dependencies.Compile[0]; core.VariantVersions != nil {
+		t.Errorf("expected no versions of variants for the same version, got %v", core.VariantVersions)
+	}
+	if result.Build.Dependencies.Compile[0].Name != "core" {
+		t.Errorf("dependencies are not sorted: %v", result.Build.Dependencies.Compile)
+	}
+	if len(release.Build.Dependencies.Compile[0].Variants) != 0 {
+		t.Errorf("source report was modified")
+	}
+}
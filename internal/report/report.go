@@ -1,6 +1,10 @@
 package report
 
-import "fmt"
+import (
+	"fmt"
+	"lampa/internal/versions"
+	"strings"
+)
 
 type Report struct {
 	Version string `json:"v"`
@@ -32,14 +36,31 @@ type BuildSegment struct {
 	VersionName   string
 	VersionCode   string
 	BuildVariant  string
+	// Variants of the combined report
+	Variants []string `json:",omitempty"`
+	// Variant of the combined report that file, SDK versions and permissions are taken from
+	BuildDataVariant string `json:",omitempty"`
 
 	MinSdkVersion     string
 	TargetSdkVersion  string
 	CompileSdkVersion string
 
+	// Permissions requested in the manifest
+	Permissions []string `json:",omitempty"`
+	// Permissions were collected (reports of older versions have none, even if the manifest requests them)
+	PermissionsCollected bool `json:",omitempty"`
+
 	// Locales []string
 
 	Dependencies DependenciesSegment
+
+	// Libraries and plugins of version catalogs (`gradle/*.versions.toml`)
+	Catalog []CatalogEntry `json:",omitempty"`
+}
+
+// HasPermissions checks if permissions of the build are known (possibly empty).
+func (self BuildSegment) HasPermissions() bool {
+	return self.PermissionsCollected || len(self.Permissions) > 0
 }
 
 type DependenciesSegment struct {
@@ -50,6 +71,120 @@ type CoordinatedDependency struct {
 	Group   string
 	Name    string
 	Version string
+
+	// Dependency is a platform/BOM
+	IsPlatform bool `json:",omitempty"`
+	// Platform/BOM ("group:name") that defined the version
+	ManagedBy string `json:",omitempty"`
+
+	// Versions requested by consumers (resolved one is in `Version`)
+	Requested []VersionRequest `json:",omitempty"`
+
+	// Newest known versions (filled by `lampa outdated`)
+	Latest *LatestVersions `json:",omitempty"`
+
+	// Project information from POM
+	Pom *PomSegment `json:",omitempty"`
+
+	// Resolved artifact files (from Gradle cache)
+	Files []ArtifactFile `json:",omitempty"`
+
+	// Repository the dependency was resolved from
+	Repository *RepositorySegment `json:",omitempty"`
+
+	// Build variants using the dependency (only in combined reports)
+	Variants []string `json:",omitempty"`
+	// Versions resolved by build variants if they differ (only in combined reports, `Version` is of the first variant)
+	VariantVersions map[string]string `json:",omitempty"`
+
+	// Where direct dependency is declared in the project
+	Declaration *DeclarationSegment `json:",omitempty"`
+}
+
+type DeclarationSegment struct {
+	// Build script (or catalog if it's not referenced by build scripts directly), relative to project root
+	File string
+	Line int
+
+	// Version catalog name ("libs") and alias ("androidx-core-ktx")
+	Catalog     string `json:",omitempty"`
+	Alias       string `json:",omitempty"`
+	CatalogFile string `json:",omitempty"`
+	CatalogLine int    `json:",omitempty"`
+}
+
+// Accessor returns catalog accessor ("libs.androidx.core.ktx") or empty string.
+func (self DeclarationSegment) Accessor() string {
+	if self.Alias == "" {
+		return ""
+	}
+	return CatalogAccessor(self.Catalog, self.Alias, false)
+}
+
+type CatalogEntry struct {
+	Catalog string
+	Alias   string
+	// "group:name" of library or plugin id
+	Module string
+	// Declared version (references are resolved)
+	Version    string `json:",omitempty"`
+	VersionRef string `json:",omitempty"`
+	IsPlugin   bool   `json:",omitempty"`
+}
+
+func (self CatalogEntry) Accessor() string {
+	return CatalogAccessor(self.Catalog, self.Alias, self.IsPlugin)
+}
+
+// CatalogAccessor builds accessor of catalog alias as used in build scripts.
+func CatalogAccessor(catalog string, alias string, isPlugin bool) string {
+	path := strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+	if isPlugin {
+		path = "plugins." + path
+	}
+	return catalog + "." + path
+}
+
+type RepositorySegment struct {
+	// Display name (e.g. "Google Maven" or repository name from the build)
+	Name string
+	Url  string `json:",omitempty"`
+}
+
+// Key identifies repository regardless of display name.
+func (self RepositorySegment) Key() string {
+	if self.Url != "" {
+		return strings.TrimSuffix(self.Url, "/")
+	}
+	return self.Name
+}
+
+type ArtifactFile struct {
+	Name   string
+	Sha256 string
+	// Several cached copies with different content were found (all of them are listed)
+	Ambiguous bool `json:",omitempty"`
+}
+
+type PomSegment struct {
+	Name         string   `json:",omitempty"`
+	Description  string   `json:",omitempty"`
+	Url          string   `json:",omitempty"`
+	ScmUrl       string   `json:",omitempty"`
+	Organization string   `json:",omitempty"`
+	Developers   []string `json:",omitempty"`
+}
+
+type LatestVersions struct {
+	Stable     string `json:",omitempty"`
+	PreRelease string `json:",omitempty"`
+}
+
+type VersionRequest struct {
+	Version     string
+	RequestedBy []string `json:",omitempty"`
+	// Requested only by dependency constraints (e.g. of platforms)
+	Constraint bool `json:",omitempty"`
 }
 
 type ContextSegment struct {
@@ -70,3 +205,50 @@ type GitSegment struct {
 func (self CoordinatedDependency) String() string {
 	return fmt.Sprintf("%s:%s:%s", self.Group, self.Name, self.Version)
 }
+
+func (self CoordinatedDependency) Coordinate() string {
+	return fmt.Sprintf("%s:%s", self.Group, self.Name)
+}
+
+func (self CoordinatedDependency) Stability() versions.Stability {
+	return versions.Classify(self.Version)
+}
+
+// ConflictingRequests returns requests that are not satisfied by the resolved version.
+// Dynamic versions and ranges are conflicting only if the resolved version doesn't match them,
+// requests of dependency constraints are never conflicting.
+func (self CoordinatedDependency) ConflictingRequests() []VersionRequest {
+	result := []VersionRequest{}
+	for _, r := range self.Requested {
+		if r.Constraint || versions.Matches(r.Version, self.Version) {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+func (self CoordinatedDependency) HasConflict() bool {
+	return len(self.ConflictingRequests()) > 0
+}
+
+// IsBehindLatest checks if there is a newer version (stable or pre-release for unstable dependencies).
+func (self CoordinatedDependency) IsBehindLatest() bool {
+	_, ok := self.LatestTarget()
+	return ok
+}
+
+// LatestTarget returns newer version the dependency can be updated to:
+// stable one if it's later, otherwise pre-release (for unstable dependencies only).
+func (self CoordinatedDependency) LatestTarget() (string, bool) {
+	if self.Latest == nil {
+		return "", false
+	}
+	if self.Latest.Stable != "" && versions.IsLater(self.Latest.Stable, self.Version) {
+		return self.Latest.Stable, true
+	}
+	if !self.Stability().IsStable() && self.Latest.PreRelease != "" && versions.IsLater(self.Latest.PreRelease, self.Version) {
+		return self.Latest.PreRelease, true
+	}
+	return "", false
+}
@@ -0,0 +1,31 @@
+package report
+
+import "testing"
+
+func TestLatestTarget(t *testing.T) {
+	cases := []struct {
+		version string
+		latest  LatestVersions
+		target  string
+		ok      bool
+	}{
+		{"1.0.0", LatestVersions{Stable: "1.0.1"}, "1.0.1", true},
+		{"1.0.1", LatestVersions{Stable: "1.0.1", PreRelease: "1.1.0-alpha01"}, "", false},
+		// Newer stable goes first
+		{"1.1.0-alpha01", LatestVersions{Stable: "1.1.0", PreRelease: "1.2.0-alpha01"}, "1.1.0", true},
+		// Behind only a newer pre-release
+		{"1.1.0-alpha01", LatestVersions{Stable: "1.0.1", PreRelease: "1.1.0-alpha02"}, "1.1.0-alpha02", true},
+		{"1.1.0-alpha01", LatestVersions{PreRelease: "1.1.0-alpha02"}, "1.1.0-alpha02", true},
+		{"1.1.0-alpha02", LatestVersions{Stable: "1.0.1", PreRelease: "1.1.0-alpha02"}, "", false},
+	}
+	for _, tc := range cases {
+		d := CoordinatedDependency{Group: "a", Name: "b", Version: tc.version, Latest: &tc.latest}
+		target, ok := d.LatestTarget()
+		if target != tc.target || ok != tc.ok {
+			t.Errorf("%s %+v: got %q %v, want %q %v", tc.version, tc.latest, target, ok, tc.target, tc.ok)
+		}
+		if d.IsBehindLatest() != tc.ok {
+			t.Errorf("%s %+v: IsBehindLatest() != %v", tc.version, tc.latest, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package reportdir
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"lampa/internal/report"
+)
+
+// Entry is a report found in the directory.
+type Entry struct {
+	// Path relative to the directory (with "/" separators)
+	Name string `json:"name"`
+
+	App         string `json:"app"`
+	VersionName string `json:"versionName"`
+	VersionCode string `json:"versionCode"`
+	Variant     string `json:"variant"`
+	Commit      string `json:"commit"`
+	Branch      string `json:"branch"`
+	Tag         string `json:"tag"`
+
+	GenerationTime string `json:"generationTime"`
+	Dependencies   int    `json:"dependencies"`
+}
+
+type indexedReport struct {
+	modTime time.Time
+	report  *report.Report
+}
+
+// Index keeps reports of the directory. Files are re-read only when they are modified.
+type Index struct {
+	Dir string
+
+	mu      sync.Mutex
+	reports map[string]indexedReport
+}
+
+func NewIndex(dir string) *Index {
+	return &Index{Dir: dir, reports: map[string]indexedReport{}}
+}
+
+// Snapshot is the result of a single scan of the directory.
+type Snapshot struct {
+	Entries []Entry
+
+	reports map[string]*report.Report
+}
+
+// Get returns report by its name (see `Entry.Name`).
+func (self Snapshot) Get(name string) (*report.Report, bool) {
+	r, ok := self.reports[name]
+	return r, ok
+}
+
+// Scan looks for reports in the directory (including subdirectories).
+// JSON files that are not Lampa reports are skipped.
+func (self *Index) Scan() (Snapshot, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	found := map[string]bool{}
+	err := filepath.WalkDir(self.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(self.Dir, path)
+		if err != nil {
+			return nil
+		}
+		name := filepath.ToSlash(rel)
+
+		if cached, ok := self.reports[name]; ok && cached.modTime.Equal(info.ModTime()) {
+			found[name] = cached.report != nil
+			return nil
+		}
+		r := readReport(path)
+		self.reports[name] = indexedReport{modTime: info.ModTime(), report: r}
+		found[name] = r != nil
+		return nil
+	})
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	result := Snapshot{Entries: []Entry{}, reports: map[string]*report.Report{}}
+	for name, cached := range self.reports {
+		if !found[name] {
+			if _, ok := found[name]; !ok {
+				delete(self.reports, name)
+			}
+			continue
+		}
+		result.Entries = append(result.Entries, newEntry(name, cached.report))
+		result.reports[name] = cached.report
+	}
+	// Newest first
+	slices.SortFunc(result.Entries, func(a, b Entry) int {
+		if c := strings.Compare(b.GenerationTime, a.GenerationTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+func readReport(path string) *report.Report {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	r := &report.Report{}
+	if err := json.Unmarshal(data, r); err != nil || r.Version == "" {
+		return nil
+	}
+	return r
+}
+
+func newEntry(name string, r *report.Report) Entry {
+	return Entry{
+		Name:           name,
+		App:            r.Build.AppName,
+		VersionName:    r.Build.VersionName,
+		VersionCode:    r.Build.VersionCode,
+		Variant:        r.Build.BuildVariant,
+		Commit:         r.Context.Git.Commit,
+		Branch:         r.Context.Git.Branch,
+		Tag:            r.Context.Git.Tag,
+		GenerationTime: r.Context.GenerationTime,
+		Dependencies:   len(r.Build.Dependencies.Compile),
+	}
+}
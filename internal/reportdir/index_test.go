@@ -0,0 +1,62 @@
+package reportdir
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lampa/internal/report"
+)
+
+func writeReport(t *testing.T, path string, versionName string, generationTime string) {
+	t.Helper()
+	r := report.Report{Version: "stats/0.0.1"}
+	r.Build.AppName = "App"
+	r.Build.VersionName = versionName
+	r.Context.GenerationTime = generationTime
+	data, _ := json.Marshal(r)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndex_Scan(t *testing.T) {
+	dir := t.TempDir()
+	writeReport(t, filepath.Join(dir, "v1.json"), "1.0", "2025-01-01T00:00:00Z")
+	writeReport(t, filepath.Join(dir, "nested", "v2.json"), "2.0", "2025-02-01T00:00:00Z")
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name": "not a report"}`), 0644)
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{`), 0644)
+
+	index := NewIndex(dir)
+	snapshot, err := index.Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := snapshot.Entries
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 reports, got %v", entries)
+	}
+	if entries[0].Name != "nested/v2.json" || entries[1].Name != "v1.json" {
+		t.Errorf("expected newest report first, got %v", entries)
+	}
+
+	if r, ok := snapshot.Get("v1.json"); !ok || r.Build.VersionName != "1.0" {
+		t.Errorf("could not get report: %v", r)
+	}
+	if _, ok := snapshot.Get("package.json"); ok {
+		t.Errorf("non-report file is returned")
+	}
+
+	os.Remove(filepath.Join(dir, "v1.json"))
+	snapshot, _ = index.Scan()
+	if len(snapshot.Entries) != 1 {
+		t.Errorf("removed report is still listed: %v", snapshot.Entries)
+	}
+	if _, ok := snapshot.Get("v1.json"); ok {
+		t.Errorf("removed report is returned")
+	}
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Well-known public repositories (matched by URL prefix).
+var Known = []struct {
+	Name     string
+	Prefixes []string
+}{
+	{"Google Maven", []string{"https://dl.google.com/dl/android/maven2", "https://maven.google.com"}},
+	{"Maven Central", []string{"https://repo.maven.apache.org/maven2", "https://repo1.maven.org/maven2"}},
+	{"Gradle Plugin Portal", []string{"https://plugins.gradle.org/m2"}},
+	{"JitPack", []string{"https://jitpack.io", "https://www.jitpack.io"}},
+	{"Sonatype Snapshots", []string{"https://oss.sonatype.org/content/repositories/snapshots", "https://s01.oss.sonatype.org/content/repositories/snapshots", "https://central.sonatype.com/repository/maven-snapshots"}},
+}
+
+// DisplayName returns human-readable repository name:
+// known public repository name, "Maven Local", "Local directory" or build-defined name.
+func DisplayName(name string, repoUrl string) string {
+	normalized := strings.TrimSuffix(strings.Replace(repoUrl, "http://", "https://", 1), "/")
+	for _, known := range Known {
+		for _, prefix := range known.Prefixes {
+			if strings.HasPrefix(normalized, prefix) {
+				return known.Name
+			}
+		}
+	}
+
+	if IsLocal(repoUrl) {
+		if name == "MavenLocal" || strings.HasSuffix(strings.TrimSuffix(repoUrl, "/"), "/.m2/repository") {
+			return "Maven Local"
+		}
+		return "Local directory"
+	}
+
+	if name != "" {
+		return name
+	}
+	if u, err := url.Parse(repoUrl); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return repoUrl
+}
+
+// IsLocal reports whether repository is located on file system (mavenLocal or flatDir).
+func IsLocal(repoUrl string) bool {
+	return strings.HasPrefix(repoUrl, "file:")
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import "testing"
+
+func TestDisplayName(t *testing.T) {
+	cases := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"Google", "https://dl.google.com/dl/android/maven2/", "Google Maven"},
+		{"MavenRepo", "https://repo.maven.apache.org/maven2/", "Maven Central"},
+		{"maven", "https://jitpack.io", "JitPack"},
+		{"MavenLocal", "file:/home/user/.m2/repository/", "Maven Local"},
+		{"flatDir", "file:/project/libs/", "Local directory"},
+		{"CompanyNexus", "https://nexus.example.com/repository/maven/", "CompanyNexus"},
+		{"", "https://nexus.example.com/repository/maven/", "nexus.example.com"},
+	}
+	for _, c := range cases {
+		if actual := DisplayName(c.name, c.url); actual != c.expected {
+			t.Errorf("DisplayName(%q, %q) = %q, expected %q", c.name, c.url, actual, c.expected)
+		}
+	}
+}
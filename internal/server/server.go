@@ -0,0 +1,165 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"lampa/internal/changelog"
+	"lampa/internal/config"
+	"lampa/internal/diff"
+	"lampa/internal/gradlecache"
+	"lampa/internal/report"
+	"lampa/internal/reportdir"
+	pages "lampa/internal/templates/html"
+	"lampa/internal/templates/html/compare"
+	serverpages "lampa/internal/templates/html/server"
+)
+
+// Server serves reports of the directory as HTML pages and JSON API:
+//
+//	GET /                          list of reports
+//	GET /reports/{name}            report page
+//	GET /compare?r=a.json&r=b.json comparison page (matrix for more than two reports)
+//	GET /api/reports               list of reports
+//	GET /api/reports/{name}        report
+//	GET /api/compare?r=a&r=b       comparison (matrix for more than two reports)
+type Server struct {
+	Index  *reportdir.Index
+	Config config.Config
+}
+
+func New(dir string, cfg config.Config) *Server {
+	return &Server{Index: reportdir.NewIndex(dir), Config: cfg}
+}
+
+func (self *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", self.handleIndexPage)
+	mux.HandleFunc("GET /reports/{name...}", self.handleReportPage)
+	mux.HandleFunc("GET /compare", self.handleComparePage)
+	mux.HandleFunc("GET /api/reports", self.handleReportsApi)
+	mux.HandleFunc("GET /api/reports/{name...}", self.handleReportApi)
+	mux.HandleFunc("GET /api/compare", self.handleCompareApi)
+	return mux
+}
+
+func (self *Server) handleIndexPage(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := self.Index.Scan()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	serverpages.IndexHtml(self.Index.Dir, snapshot.Entries).Render(r.Context(), w)
+}
+
+func (self *Server) handleReportPage(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := self.Index.Scan()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report, ok := snapshot.Get(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	pages.CollectHtml(report).Render(r.Context(), w)
+}
+
+func (self *Server) handleComparePage(w http.ResponseWriter, r *http.Request) {
+	reports, err := self.selectedReports(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if len(reports) == 2 {
+		c := self.compare(reports[0], reports[1])
+		compare.CompareHtml(reports[0], reports[1], c).Render(r.Context(), w)
+	} else {
+		m := diff.CompareMatrix(reports, self.Config.Compare.Rules)
+		compare.MatrixHtml(reports, m).Render(r.Context(), w)
+	}
+}
+
+func (self *Server) handleReportsApi(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := self.Index.Scan()
+	if err != nil {
+		writeJsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJson(w, snapshot.Entries)
+}
+
+func (self *Server) handleReportApi(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := self.Index.Scan()
+	if err != nil {
+		writeJsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+	report, ok := snapshot.Get(r.PathValue("name"))
+	if !ok {
+		writeJsonError(w, http.StatusNotFound, fmt.Errorf("report %q not found", r.PathValue("name")))
+		return
+	}
+	writeJson(w, report)
+}
+
+func (self *Server) handleCompareApi(w http.ResponseWriter, r *http.Request) {
+	reports, err := self.selectedReports(r)
+	if err != nil {
+		writeJsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(reports) == 2 {
+		writeJson(w, self.compare(reports[0], reports[1]))
+	} else {
+		writeJson(w, diff.CompareMatrix(reports, self.Config.Compare.Rules))
+	}
+}
+
+// selectedReports returns reports from `r` query parameters (in order).
+func (self *Server) selectedReports(r *http.Request) ([]*report.Report, error) {
+	names := r.URL.Query()["r"]
+	if len(names) < 2 {
+		return nil, fmt.Errorf("select at least two reports")
+	}
+
+	snapshot, err := self.Index.Scan()
+	if err != nil {
+		return nil, err
+	}
+	result := []*report.Report{}
+	for _, name := range names {
+		report, ok := snapshot.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("report %q not found", name)
+		}
+		result = append(result, report)
+	}
+	return result, nil
+}
+
+func (self *Server) compare(r1 *report.Report, r2 *report.Report) diff.Comparison {
+	c := diff.Compare(r1, r2, self.Config)
+	c.Dependencies.AddChangelogLinks(changelog.DefaultChain(self.Config, gradlecache.New(gradlecache.DefaultRoot())))
+	return c
+}
+
+func writeJson(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+func writeJsonError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
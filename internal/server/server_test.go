@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lampa/internal/config"
+	"lampa/internal/report"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+	for name, deps := range map[string][]report.CoordinatedDependency{
+		"v1.json": {{Group: "a", Name: "x", Version: "1.0"}},
+		"v2.json": {{Group: "a", Name: "x", Version: "1.1"}, {Group: "a", Name: "y", Version: "1.0"}},
+		"v3.json": {{Group: "a", Name: "y", Version: "1.0"}},
+	} {
+		r := report.Report{Version: "stats/0.0.1"}
+		r.Build.AppName = "App"
+		r.Build.VersionName = strings.TrimSuffix(name, ".json")
+		r.Build.Dependencies.Compile = deps
+		data, _ := json.Marshal(r)
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srv := httptest.NewServer(New(dir, config.Config{}).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestServer_Api(t *testing.T) {
+	srv := newTestServer(t)
+
+	status, body := get(t, srv.URL+"/api/reports")
+	if status != http.StatusOK || !strings.Contains(body, `"name": "v1.json"`) {
+		t.Errorf("unexpected reports list (%d): %s", status, body)
+	}
+
+	status, body = get(t, srv.URL+"/api/reports/v2.json")
+	if status != http.StatusOK || !strings.Contains(body, `"VersionName": "v2"`) {
+		t.Errorf("unexpected report (%d): %s", status, body)
+	}
+
+	status, _ = get(t, srv.URL+"/api/reports/missing.json")
+	if status != http.StatusNotFound {
+		t.Errorf("expected 404 for missing report, got %d", status)
+	}
+
+	status, body = get(t, srv.URL+"/api/compare?r=v1.json&r=v2.json")
+	if status != http.StatusOK || !strings.Contains(body, `"Upgraded"`) {
+		t.Errorf("unexpected comparison (%d): %s", status, body)
+	}
+
+	status, body = get(t, srv.URL+"/api/compare?r=v1.json&r=v2.json&r=v3.json")
+	if status != http.StatusOK || !strings.Contains(body, `"Columns"`) {
+		t.Errorf("unexpected matrix (%d): %s", status, body)
+	}
+
+	status, _ = get(t, srv.URL+"/api/compare?r=v1.json")
+	if status != http.StatusBadRequest {
+		t.Errorf("expected 400 for single report, got %d", status)
+	}
+}
+
+func TestServer_Pages(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, path := range []string{"/", "/reports/v1.json", "/compare?r=v1.json&r=v2.json", "/compare?r=v1.json&r=v2.json&r=v3.json"} {
+		status, body := get(t, srv.URL+path)
+		if status != http.StatusOK || !strings.Contains(body, "<html>") {
+			t.Errorf("%s: unexpected response (%d)", path, status)
+		}
+	}
+}
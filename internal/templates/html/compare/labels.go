@@ -0,0 +1,56 @@
+package compare
+
+import (
+	"fmt"
+	"lampa/internal/diff"
+	"lampa/internal/report"
+)
+
+// Labels are section captions that depend on comparison mode.
+type Labels struct {
+	Subject string
+
+	New        string
+	Removed    string
+	Upgraded   string
+	Downgraded string
+	Changed    string
+	Unchanged  string
+
+	// Conflicts, unstable dependencies and repositories that are present only in the second report
+	Introduced      string
+	NewRepositories string
+	MovedRepository string
+}
+
+func NewLabels(r1 *report.Report, r2 *report.Report, mode diff.Mode) Labels {
+	if mode == diff.ModeVariants {
+		v1 := r1.Build.BuildVariant
+		v2 := r2.Build.BuildVariant
+		return Labels{
+			Subject:         "variants",
+			New:             fmt.Sprintf("Only in %s", v2),
+			Removed:         fmt.Sprintf("Only in %s", v1),
+			Upgraded:        fmt.Sprintf("Newer in %s", v2),
+			Downgraded:      fmt.Sprintf("Older in %s", v2),
+			Changed:         "Different, uncomparable",
+			Unchanged:       "Same in both",
+			Introduced:      fmt.Sprintf("Only in %s", v2),
+			NewRepositories: fmt.Sprintf("Used only by %s", v2),
+			MovedRepository: "Resolved from different repositories",
+		}
+	}
+
+	return Labels{
+		Subject:         "versions",
+		New:             "New",
+		Removed:         "Removed",
+		Upgraded:        "Upgraded",
+		Downgraded:      "Downgraded",
+		Changed:         "Changed, uncomparable",
+		Unchanged:       "Unchanged",
+		Introduced:      "Introduced",
+		NewRepositories: "New repositories",
+		MovedRepository: "Moved between repositories",
+	}
+}
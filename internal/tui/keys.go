@@ -0,0 +1,104 @@
+package tui
+
+import "unicode/utf8"
+
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyTab
+	KeyBackTab
+	KeyBackspace
+	KeyEscape
+	KeyCtrlC
+)
+
+type Key struct {
+	Code KeyCode
+	// Set for KeyRune
+	Rune rune
+}
+
+var escapeSequences = map[string]KeyCode{
+	"\x1b[A":  KeyUp,
+	"\x1b[B":  KeyDown,
+	"\x1b[C":  KeyRight,
+	"\x1b[D":  KeyLeft,
+	"\x1bOA":  KeyUp,
+	"\x1bOB":  KeyDown,
+	"\x1bOC":  KeyRight,
+	"\x1bOD":  KeyLeft,
+	"\x1b[5~": KeyPageUp,
+	"\x1b[6~": KeyPageDown,
+	"\x1b[H":  KeyHome,
+	"\x1b[F":  KeyEnd,
+	"\x1b[1~": KeyHome,
+	"\x1b[4~": KeyEnd,
+	"\x1b[Z":  KeyBackTab,
+}
+
+// ParseKeys decodes keys from terminal input (in raw mode).
+func ParseKeys(data []byte) []Key {
+	result := []Key{}
+	for len(data) > 0 {
+		if data[0] == 0x1b {
+			matched := false
+			for seq, code := range escapeSequences {
+				if len(data) >= len(seq) && string(data[:len(seq)]) == seq {
+					result = append(result, Key{Code: code})
+					data = data[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				// Lone escape or unsupported sequence
+				result = append(result, Key{Code: KeyEscape})
+				data = skipEscapeSequence(data)
+			}
+			continue
+		}
+
+		switch data[0] {
+		case 0x03:
+			result = append(result, Key{Code: KeyCtrlC})
+		case '\r', '\n':
+			result = append(result, Key{Code: KeyEnter})
+		case '\t':
+			result = append(result, Key{Code: KeyTab})
+		case 0x7f, 0x08:
+			result = append(result, Key{Code: KeyBackspace})
+		default:
+			r, size := utf8.DecodeRune(data)
+			if r >= 0x20 {
+				result = append(result, Key{Code: KeyRune, Rune: r})
+			}
+			data = data[size:]
+			continue
+		}
+		data = data[1:]
+	}
+	return result
+}
+
+// skipEscapeSequence drops unknown CSI sequence (or a single escape byte).
+func skipEscapeSequence(data []byte) []byte {
+	if len(data) < 2 || data[1] != '[' {
+		return data[1:]
+	}
+	for i := 2; i < len(data); i++ {
+		if data[i] >= 0x40 && data[i] <= 0x7e {
+			return data[i+1:]
+		}
+	}
+	return nil
+}
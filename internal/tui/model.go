@@ -0,0 +1,475 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"lampa/internal/config"
+	"lampa/internal/diff"
+	"lampa/internal/report"
+	"lampa/internal/templates"
+	"lampa/internal/templates/html/compare"
+
+	"github.com/samber/lo"
+)
+
+type Tab int
+
+const (
+	TabBuild Tab = iota
+	TabDependencies
+	TabDiff
+	TabTree
+)
+
+func (self Tab) Name() string {
+	switch self {
+	case TabBuild:
+		return "Build"
+	case TabDependencies:
+		return "Dependencies"
+	case TabDiff:
+		return "Diff"
+	case TabTree:
+		return "Tree"
+	}
+	return ""
+}
+
+type rowStyle int
+
+const (
+	styleNormal rowStyle = iota
+	styleHeader
+	styleAdded
+	styleRemoved
+	styleUpgraded
+	styleDowngraded
+	styleChanged
+	styleWarning
+)
+
+type row struct {
+	Text  string
+	Style rowStyle
+	// Path of tree node (only in tree tab)
+	Path string
+	// Tree node has children
+	IsExpandable bool
+}
+
+// Filters of dependencies tab
+var dependencyFilters = []string{"All", "Unstable", "Conflicts", "Behind latest", "Platforms"}
+
+// Filters of diff tab
+var diffFilters = []string{"All", "New", "Removed", "Upgraded", "Downgraded", "Changed", "Unchanged"}
+
+// Model is a state of terminal UI: it handles keys and renders screen lines.
+type Model struct {
+	// Report that is shown (the newer one if two reports are compared)
+	Report *report.Report
+	// Previous report (optional)
+	Before     *report.Report
+	Comparison *diff.Comparison
+
+	Tabs []Tab
+	tab  int
+
+	tree     Tree
+	expanded map[string]bool
+
+	cursor map[Tab]int
+	offset map[Tab]int
+	filter map[Tab]int
+
+	query       string
+	isSearching bool
+
+	IsDone bool
+}
+
+// NewModel creates model for the report or for comparison of two reports (if `after` is set).
+func NewModel(r *report.Report, after *report.Report, cfg config.Config) *Model {
+	m := &Model{
+		Report:   r,
+		Tabs:     []Tab{TabBuild, TabDependencies, TabTree},
+		expanded: map[string]bool{},
+		cursor:   map[Tab]int{},
+		offset:   map[Tab]int{},
+		filter:   map[Tab]int{},
+	}
+	if after != nil {
+		c := diff.Compare(r, after, cfg)
+		m.Before = r
+		m.Report = after
+		m.Comparison = &c
+		m.Tabs = []Tab{TabBuild, TabDependencies, TabDiff, TabTree}
+	}
+
+	m.tree = BuildTree(m.Report)
+	// Modules are expanded initially
+	for _, root := range m.tree.Roots {
+		m.expanded[root.Key] = true
+	}
+	return m
+}
+
+func (self *Model) CurrentTab() Tab {
+	return self.Tabs[self.tab]
+}
+
+func (self *Model) Query() string {
+	return self.query
+}
+
+// Update handles key press. `height` is the number of visible content rows.
+func (self *Model) Update(key Key, height int) {
+	if self.isSearching {
+		self.updateSearch(key)
+		return
+	}
+
+	tab := self.CurrentTab()
+	rows := self.rows()
+	switch key.Code {
+	case KeyCtrlC:
+		self.IsDone = true
+	case KeyTab:
+		self.tab = (self.tab + 1) % len(self.Tabs)
+	case KeyBackTab:
+		self.tab = (self.tab + len(self.Tabs) - 1) % len(self.Tabs)
+	case KeyUp:
+		self.cursor[tab]--
+	case KeyDown:
+		self.cursor[tab]++
+	case KeyPageUp:
+		self.cursor[tab] -= height
+	case KeyPageDown:
+		self.cursor[tab] += height
+	case KeyHome:
+		self.cursor[tab] = 0
+	case KeyEnd:
+		self.cursor[tab] = len(rows) - 1
+	case KeyEnter:
+		self.toggle(rows)
+	case KeyRight:
+		self.setExpanded(rows, true)
+	case KeyLeft:
+		self.setExpanded(rows, false)
+	case KeyEscape:
+		self.query = ""
+	case KeyRune:
+		switch key.Rune {
+		case 'q':
+			self.IsDone = true
+		case 'j':
+			self.cursor[tab]++
+		case 'k':
+			self.cursor[tab]--
+		case 'g':
+			self.cursor[tab] = 0
+		case 'G':
+			self.cursor[tab] = len(rows) - 1
+		case ' ':
+			self.toggle(rows)
+		case '/':
+			self.isSearching = true
+		case 'f':
+			if filters := self.filters(); len(filters) > 0 {
+				self.filter[tab] = (self.filter[tab] + 1) % len(filters)
+				self.cursor[tab] = 0
+			}
+		default:
+			if key.Rune >= '1' && key.Rune <= '9' && int(key.Rune-'1') < len(self.Tabs) {
+				self.tab = int(key.Rune - '1')
+			}
+		}
+	}
+	self.clampCursor(height)
+}
+
+func (self *Model) updateSearch(key Key) {
+	switch key.Code {
+	case KeyEnter:
+		self.isSearching = false
+	case KeyEscape, KeyCtrlC:
+		self.isSearching = false
+		self.query = ""
+	case KeyBackspace:
+		if runes := []rune(self.query); len(runes) > 0 {
+			self.query = string(runes[:len(runes)-1])
+		}
+	case KeyRune:
+		self.query += string(key.Rune)
+	}
+	self.cursor[self.CurrentTab()] = 0
+	self.offset[self.CurrentTab()] = 0
+}
+
+func (self *Model) toggle(rows []row) {
+	tab := self.CurrentTab()
+	if tab != TabTree || len(rows) == 0 {
+		return
+	}
+	r := rows[self.cursor[tab]]
+	if r.IsExpandable {
+		self.expanded[r.Path] = !self.expanded[r.Path]
+	}
+}
+
+func (self *Model) setExpanded(rows []row, expanded bool) {
+	tab := self.CurrentTab()
+	if tab != TabTree || len(rows) == 0 {
+		return
+	}
+	r := rows[self.cursor[tab]]
+	if r.IsExpandable {
+		self.expanded[r.Path] = expanded
+	}
+}
+
+func (self *Model) clampCursor(height int) {
+	tab := self.CurrentTab()
+	count := len(self.rows())
+	self.cursor[tab] = max(0, min(self.cursor[tab], count-1))
+
+	if height <= 0 {
+		return
+	}
+	if self.cursor[tab] < self.offset[tab] {
+		self.offset[tab] = self.cursor[tab]
+	}
+	if self.cursor[tab] >= self.offset[tab]+height {
+		self.offset[tab] = self.cursor[tab] - height + 1
+	}
+	self.offset[tab] = max(0, min(self.offset[tab], count-height))
+}
+
+func (self *Model) filters() []string {
+	switch self.CurrentTab() {
+	case TabDependencies:
+		return dependencyFilters
+	case TabDiff:
+		return diffFilters
+	}
+	return nil
+}
+
+// FilterName returns name of the current filter (empty if tab has no filters).
+func (self *Model) FilterName() string {
+	filters := self.filters()
+	if len(filters) == 0 {
+		return ""
+	}
+	return filters[self.filter[self.CurrentTab()]]
+}
+
+func (self *Model) rows() []row {
+	var rows []row
+	switch self.CurrentTab() {
+	case TabBuild:
+		rows = self.buildRows()
+	case TabDependencies:
+		rows = self.dependencyRows()
+	case TabDiff:
+		rows = self.diffRows()
+	case TabTree:
+		return self.treeRows()
+	}
+
+	if self.query == "" {
+		return rows
+	}
+	query := strings.ToLower(self.query)
+	return lo.Filter(rows, func(r row, _ int) bool {
+		return r.Style == styleHeader || strings.Contains(strings.ToLower(r.Text), query)
+	})
+}
+
+func (self *Model) buildRows() []row {
+	r1 := self.Before
+	r2 := self.Report
+	value := func(get func(r *report.Report) string) string {
+		if r1 == nil || get(r1) == get(r2) {
+			return get(r2)
+		}
+		return fmt.Sprintf("%s → %s", get(r1), get(r2))
+	}
+	item := func(name string, get func(r *report.Report) string) row {
+		return row{Text: fmt.Sprintf("  %-18s %s", name, value(get))}
+	}
+
+	return []row{
+		{Text: "Application", Style: styleHeader},
+		item("Application Id", func(r *report.Report) string { return r.Build.ApplicationId }),
+		item("Name", func(r *report.Report) string { return r.Build.AppName }),
+		item("Build Variant", func(r *report.Report) string { return r.Build.BuildVariant }),
+		item("Version Name", func(r *report.Report) string { return r.Build.VersionName }),
+		item("Version Code", func(r *report.Report) string { return r.Build.VersionCode }),
+		{Text: "SDK", Style: styleHeader},
+		item("Min SDK", func(r *report.Report) string { return r.Build.MinSdkVersion }),
+		item("Target SDK", func(r *report.Report) string { return r.Build.TargetSdkVersion }),
+		item("Compile SDK", func(r *report.Report) string { return r.Build.CompileSdkVersion }),
+		{Text: "Git", Style: styleHeader},
+		item("Branch", func(r *report.Report) string { return r.Context.Git.Branch }),
+		item("Tag", func(r *report.Report) string { return r.Context.Git.Tag }),
+		item("Commit", func(r *report.Report) string { return r.Context.Git.Commit }),
+		{Text: "File", Style: styleHeader},
+		item("Name", func(r *report.Report) string { return r.Build.AabName }),
+		item("Size", func(r *report.Report) string { return templates.FormatFileSize(r.Build.AabSize) }),
+		item("SHA1", func(r *report.Report) string { return r.Build.AabSha1 }),
+		{Text: "Tool", Style: styleHeader},
+		item("Version", func(r *report.Report) string { return r.Context.Tool.Version }),
+		item("Generated", func(r *report.Report) string { return templates.FormatGenerationTime(r.Context.GenerationTime) }),
+	}
+}
+
+func (self *Model) dependencyRows() []row {
+	filter := dependencyFilters[self.filter[TabDependencies]]
+	deps := lo.Filter(self.Report.Build.Dependencies.Compile, func(d report.CoordinatedDependency, _ int) bool {
+		switch filter {
+		case "Unstable":
+			return !d.Stability().IsStable()
+		case "Conflicts":
+			return d.HasConflict()
+		case "Behind latest":
+			return d.IsBehindLatest()
+		case "Platforms":
+			return d.IsPlatform
+		}
+		return true
+	})
+
+	rows := []row{{Text: fmt.Sprintf("Compile-Time (%d)", len(deps)), Style: styleHeader}}
+	for _, d := range deps {
+		text := "  " + d.String()
+		style := styleNormal
+		if !d.Stability().IsStable() {
+			text += " [" + d.Stability().Label() + "]"
+			style = styleWarning
+		}
+		if d.IsPlatform {
+			text += " (BOM)"
+		}
+		if d.ManagedBy != "" {
+			text += " (managed by " + d.ManagedBy + ")"
+		}
+		for _, r := range d.ConflictingRequests() {
+			text += " (requested " + r.Version + ")"
+		}
+		if target, ok := d.LatestTarget(); ok {
+			text += " (latest " + target + ")"
+		}
+		if d.Repository != nil {
+			text += " from " + d.Repository.Name
+		}
+		rows = append(rows, row{Text: text, Style: style})
+	}
+	return rows
+}
+
+func (self *Model) diffRows() []row {
+	if self.Comparison == nil {
+		return nil
+	}
+	deps := self.Comparison.Dependencies
+	labels := compare.NewLabels(self.Before, self.Report, self.Comparison.Mode)
+	categories := []struct {
+		name  string
+		label string
+		deps  []diff.Dep
+		mark  string
+		style rowStyle
+	}{
+		{"New", labels.New, deps.New, "+", styleAdded},
+		{"Removed", labels.Removed, deps.Removed, "-", styleRemoved},
+		{"Upgraded", labels.Upgraded, deps.Upgraded, "^", styleUpgraded},
+		{"Downgraded", labels.Downgraded, deps.Downgraded, "v", styleDowngraded},
+		{"Changed", labels.Changed, deps.Changed, "~", styleChanged},
+		{"Unchanged", labels.Unchanged, deps.Unchanged, "=", styleNormal},
+	}
+
+	filter := diffFilters[self.filter[TabDiff]]
+	rows := []row{}
+	for _, c := range categories {
+		if filter != "All" && filter != c.name {
+			continue
+		}
+		rows = append(rows, row{Text: fmt.Sprintf("%s (%d)", c.label, len(c.deps)), Style: styleHeader})
+		for _, d := range c.deps {
+			text := fmt.Sprintf("  %s %s: %s", c.mark, d.Name(), d.Version)
+			if len(d.Members) > 0 {
+				text += fmt.Sprintf(" (%d artifacts)", len(d.Members))
+			}
+			rows = append(rows, row{Text: text, Style: c.style})
+		}
+	}
+	return rows
+}
+
+func (self *Model) treeRows() []row {
+	rows := []row{}
+	query := strings.ToLower(self.query)
+
+	// matches reports whether node or any of its descendants matches the query
+	var matches func(node TreeNode, ancestors map[string]bool) bool
+	matches = func(node TreeNode, ancestors map[string]bool) bool {
+		if strings.Contains(strings.ToLower(node.Label), query) {
+			return true
+		}
+		if ancestors[node.Key] {
+			return false
+		}
+		ancestors[node.Key] = true
+		defer delete(ancestors, node.Key)
+		return lo.SomeBy(self.tree.Children(node), func(child TreeNode) bool {
+			return matches(child, ancestors)
+		})
+	}
+
+	var walk func(node TreeNode, path string, depth int, ancestors map[string]bool)
+	walk = func(node TreeNode, path string, depth int, ancestors map[string]bool) {
+		if query != "" && !matches(node, map[string]bool{}) {
+			return
+		}
+
+		children := self.tree.Children(node)
+		isCycle := ancestors[node.Key]
+		isExpandable := len(children) > 0 && !isCycle
+		isExpanded := isExpandable && (self.expanded[path] || query != "")
+
+		marker := "  "
+		if isExpandable {
+			marker = "▸ "
+			if isExpanded {
+				marker = "▾ "
+			}
+		}
+		text := strings.Repeat("  ", depth) + marker + node.Label
+		style := styleNormal
+		if depth == 0 {
+			style = styleHeader
+		}
+		if isCycle {
+			text += " (cycle)"
+		}
+		rows = append(rows, row{Text: text, Style: style, Path: path, IsExpandable: isExpandable})
+
+		if !isExpanded {
+			return
+		}
+		ancestors[node.Key] = true
+		defer delete(ancestors, node.Key)
+		for _, child := range children {
+			walk(child, path+"\x00"+child.Key, depth+1, ancestors)
+		}
+	}
+
+	if self.tree.IsEmpty() {
+		return []row{{Text: "Report has no dependency tree information"}}
+	}
+	for _, root := range self.tree.Roots {
+		walk(root, root.Key, 0, map[string]bool{})
+	}
+	return rows
+}
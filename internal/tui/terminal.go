@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	exitAltScreen  = "\x1b[?25h\x1b[?1049l"
+	cursorHome     = "\x1b[H"
+	clearLineEnd   = "\x1b[K"
+	clearScreenEnd = "\x1b[J"
+)
+
+// How often terminal size is checked
+const resizeCheckInterval = 250 * time.Millisecond
+
+// Run shows the model in full-screen mode until user quits or context is cancelled.
+func Run(ctx context.Context, m *Model) error {
+	in := int(os.Stdin.Fd())
+	out := int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return fmt.Errorf("interactive terminal is required")
+	}
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return fmt.Errorf("could not switch terminal to raw mode: %v", err)
+	}
+	defer term.Restore(in, state)
+
+	// Colors are disabled by the package when output is not a terminal, but raw mode doesn't change that
+	noColor := color.NoColor
+	color.NoColor = os.Getenv("NO_COLOR") != ""
+	defer func() { color.NoColor = noColor }()
+
+	fmt.Print(enterAltScreen)
+	defer fmt.Print(exitAltScreen)
+
+	keys := make(chan []byte)
+	go readInput(os.Stdin, keys)
+
+	ticker := time.NewTicker(resizeCheckInterval)
+	defer ticker.Stop()
+
+	width, height := 0, 0
+	render := func() {
+		width, height, _ = term.GetSize(out)
+		draw(os.Stdout, m.View(width, height))
+	}
+	render()
+
+	for !m.IsDone {
+		select {
+		case <-ctx.Done():
+			return nil
+		case data, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, key := range ParseKeys(data) {
+				m.Update(key, ContentHeight(height))
+			}
+			render()
+		case <-ticker.C:
+			if w, h, _ := term.GetSize(out); w != width || h != height {
+				render()
+			}
+		}
+	}
+	return nil
+}
+
+func readInput(r io.Reader, keys chan<- []byte) {
+	defer close(keys)
+	buffer := make([]byte, 256)
+	for {
+		n, err := r.Read(buffer)
+		if n > 0 {
+			keys <- append([]byte{}, buffer[:n]...)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func draw(w io.Writer, lines []string) {
+	b := &strings.Builder{}
+	b.WriteString(cursorHome)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString(clearLineEnd)
+	}
+	b.WriteString(clearScreenEnd)
+	io.WriteString(w, b.String())
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"lampa/internal/report"
+)
+
+// TreeNode is a dependency in the tree restored from requested versions of the report.
+type TreeNode struct {
+	Label string
+	// Consumer key of the node ("group:artifact:version" or module name)
+	Key string
+}
+
+// Tree is a dependency tree of the report. Children are resolved lazily, so cycles are possible.
+type Tree struct {
+	Roots    []TreeNode
+	children map[string][]TreeNode
+}
+
+const otherRootKey = "\x00other"
+
+// BuildTree restores dependency tree from consumers of requested versions (see `report.VersionRequest`).
+// Dependencies without requests are put into "Other" root.
+func BuildTree(r *report.Report) Tree {
+	result := Tree{children: map[string][]TreeNode{}}
+
+	coordinates := map[string]bool{}
+	for _, d := range r.Build.Dependencies.Compile {
+		coordinates[d.String()] = true
+	}
+
+	roots := []string{}
+	for _, d := range r.Build.Dependencies.Compile {
+		if len(d.Requested) == 0 {
+			result.children[otherRootKey] = append(result.children[otherRootKey], TreeNode{Label: d.String(), Key: d.String()})
+			continue
+		}
+		for _, request := range d.Requested {
+			label := d.String()
+			if request.Version != d.Version {
+				label = d.Coordinate() + ":" + request.Version + " → " + d.Version
+			}
+			for _, consumer := range request.RequestedBy {
+				result.children[consumer] = append(result.children[consumer], TreeNode{Label: label, Key: d.String()})
+				if !coordinates[consumer] && !slices.Contains(roots, consumer) {
+					roots = append(roots, consumer)
+				}
+			}
+		}
+	}
+
+	// Application modules go before other projects
+	slices.SortFunc(roots, func(a, b string) int {
+		pa := strings.HasPrefix(a, "project ")
+		pb := strings.HasPrefix(b, "project ")
+		if pa != pb {
+			if pa {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a, b)
+	})
+	for _, root := range roots {
+		result.Roots = append(result.Roots, TreeNode{Label: root, Key: root})
+	}
+	if len(result.children[otherRootKey]) > 0 {
+		result.Roots = append(result.Roots, TreeNode{Label: "Other", Key: otherRootKey})
+	}
+
+	for key := range result.children {
+		slices.SortFunc(result.children[key], func(a, b TreeNode) int {
+			return strings.Compare(a.Label, b.Label)
+		})
+	}
+	return result
+}
+
+func (self Tree) Children(node TreeNode) []TreeNode {
+	return self.children[node.Key]
+}
+
+func (self Tree) IsEmpty() bool {
+	return len(self.Roots) == 0
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"lampa/internal/config"
+	"lampa/internal/report"
+)
+
+func TestParseKeys(t *testing.T) {
+	keys := ParseKeys([]byte("\x1b[Aj\x1b[6~/é\r\x1b\x1b[Z\x7f\x03"))
+	expected := []Key{
+		{Code: KeyUp},
+		{Code: KeyRune, Rune: 'j'},
+		{Code: KeyPageDown},
+		{Code: KeyRune, Rune: '/'},
+		{Code: KeyRune, Rune: 'é'},
+		{Code: KeyEnter},
+		{Code: KeyEscape},
+		{Code: KeyBackTab},
+		{Code: KeyBackspace},
+		{Code: KeyCtrlC},
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+}
+
+func sampleReport() *report.Report {
+	r := &report.Report{}
+	r.Build.AppName = "App"
+	r.Build.VersionName = "1.0"
+	r.Build.Dependencies.Compile = []report.CoordinatedDependency{
+		{Group: "a", Name: "lib", Version: "1.0", Requested: []report.VersionRequest{{Version: "1.0", RequestedBy: []string{"app"}}}},
+		{Group: "a", Name: "core", Version: "2.0", Requested: []report.VersionRequest{
+			{Version: "1.5", RequestedBy: []string{"a:lib:1.0"}},
+			{Version: "2.0", RequestedBy: []string{"project core"}},
+		}},
+		{Group: "b", Name: "bom", Version: "1.0-beta01", IsPlatform: true},
+	}
+	return r
+}
+
+func TestBuildTree(t *testing.T) {
+	tree := BuildTree(sampleReport())
+
+	roots := []string{}
+	for _, root := range tree.Roots {
+		roots = append(roots, root.Label)
+	}
+	if !reflect.DeepEqual(roots, []string{"app", "project core", "Other"}) {
+		t.Fatalf("unexpected roots %v", roots)
+	}
+
+	app := tree.Children(tree.Roots[0])
+	if len(app) != 1 || app[0].Label != "a:lib:1.0" {
+		t.Fatalf("unexpected children of app %v", app)
+	}
+	lib := tree.Children(app[0])
+	if len(lib) != 1 || lib[0].Label != "a:core:1.5 → 2.0" {
+		t.Errorf("unexpected children of lib %v", lib)
+	}
+}
+
+func texts(m *Model) []string {
+	result := []string{}
+	for _, r := range m.rows() {
+		result = append(result, strings.TrimSpace(r.Text))
+	}
+	return result
+}
+
+func TestModel_Dependencies(t *testing.T) {
+	m := NewModel(sampleReport(), nil, config.Config{})
+	m.Update(Key{Code: KeyRune, Rune: '2'}, 10)
+	if m.CurrentTab() != TabDependencies {
+		t.Fatalf("expected dependencies tab, got %v", m.CurrentTab())
+	}
+	if rows := texts(m); len(rows) != 4 {
+		t.Errorf("unexpected rows %v", rows)
+	}
+
+	// Search
+	for _, key := range ParseKeys([]byte("/core\r")) {
+		m.Update(key, 10)
+	}
+	if rows := texts(m); len(rows) != 2 || !strings.HasPrefix(rows[1], "a:core:2.0") {
+		t.Errorf("unexpected search result %v", rows)
+	}
+	m.Update(Key{Code: KeyEscape}, 10)
+
+	// Unstable filter
+	m.Update(Key{Code: KeyRune, Rune: 'f'}, 10)
+	if m.FilterName() != "Unstable" {
+		t.Fatalf("unexpected filter %q", m.FilterName())
+	}
+	if rows := texts(m); len(rows) != 2 || !strings.HasPrefix(rows[1], "b:bom:1.0-beta01") {
+		t.Errorf("unexpected filtered rows %v", rows)
+	}
+}
+
+func TestModel_Tree(t *testing.T) {
+	m := NewModel(sampleReport(), nil, config.Config{})
+	m.Update(Key{Code: KeyRune, Rune: '3'}, 10)
+
+	if rows := texts(m); !reflect.DeepEqual(rows, []string{"▾ app", "▸ a:lib:1.0", "▾ project core", "a:core:2.0", "▾ Other", "b:bom:1.0-beta01"}) {
+		t.Fatalf("unexpected tree %v", rows)
+	}
+
+	m.Update(Key{Code: KeyDown}, 10)
+	m.Update(Key{Code: KeyEnter}, 10)
+	if rows := texts(m); len(rows) != 7 || rows[2] != "a:core:1.5 → 2.0" {
+		t.Errorf("node is not expanded %v", rows)
+	}
+	m.Update(Key{Code: KeyLeft}, 10)
+	if rows := texts(m); len(rows) != 6 {
+		t.Errorf("node is not collapsed %v", rows)
+	}
+}
+
+func TestModel_Diff(t *testing.T) {
+	before := sampleReport()
+	after := sampleReport()
+	after.Build.VersionName = "1.1"
+	after.Build.Dependencies.Compile[0].Version = "1.1"
+	after.Build.Dependencies.Compile = after.Build.Dependencies.Compile[:2]
+
+	m := NewModel(before, after, config.Config{})
+	if len(m.Tabs) != 4 || m.Report != after {
+		t.Fatalf("unexpected model %v", m.Tabs)
+	}
+	m.Update(Key{Code: KeyRune, Rune: '3'}, 10)
+	rows := texts(m)
+	for _, expected := range []string{"Removed (1)", "Upgraded (1)", "^ a:lib: 1.0 → 1.1"} {
+		if !strings.Contains(strings.Join(rows, "\n"), expected) {
+			t.Errorf("%q is missing in %v", expected, rows)
+		}
+	}
+
+	lines := m.View(40, 8)
+	if len(lines) != 8 {
+		t.Errorf("expected 8 screen lines, got %d", len(lines))
+	}
+}
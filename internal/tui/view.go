@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"lampa/internal/diff"
+
+	"github.com/fatih/color"
+)
+
+var (
+	titleColor      = color.New(color.Bold)
+	activeTabColor  = color.New(color.ReverseVideo, color.Bold)
+	cursorColor     = color.New(color.ReverseVideo)
+	helpColor       = color.New(color.Faint)
+	headerColor     = color.New(color.Bold)
+	addedColor      = color.New(color.FgGreen)
+	removedColor    = color.New(color.FgRed)
+	upgradedColor   = color.New(color.FgBlue)
+	downgradedColor = color.New(color.FgYellow)
+	changedColor    = color.New(color.FgMagenta)
+	warningColor    = color.New(color.FgYellow)
+)
+
+// Number of screen lines that are not used by content (title, tabs and status line)
+const chromeHeight = 3
+
+// ContentHeight returns number of content rows for the screen height.
+func ContentHeight(height int) int {
+	return max(1, height-chromeHeight)
+}
+
+// View renders screen lines.
+func (self *Model) View(width int, height int) []string {
+	contentHeight := ContentHeight(height)
+	self.clampCursor(contentHeight)
+
+	lines := []string{titleColor.Sprint(truncate(self.title(), width))}
+
+	tabs := []string{}
+	for i, tab := range self.Tabs {
+		name := fmt.Sprintf(" %d %s ", i+1, tab.Name())
+		if i == self.tab {
+			name = activeTabColor.Sprint(name)
+		}
+		tabs = append(tabs, name)
+	}
+	lines = append(lines, strings.Join(tabs, " "))
+
+	tab := self.CurrentTab()
+	rows := self.rows()
+	offset := self.offset[tab]
+	for i := offset; i < offset+contentHeight; i++ {
+		if i >= len(rows) {
+			lines = append(lines, "")
+			continue
+		}
+		text := truncate(rows[i].Text, width)
+		if i == self.cursor[tab] {
+			lines = append(lines, cursorColor.Sprint(pad(text, width)))
+		} else {
+			lines = append(lines, styleColor(rows[i].Style).Sprint(text))
+		}
+	}
+
+	lines = append(lines, self.status(width, len(rows)))
+	return lines
+}
+
+func (self *Model) title() string {
+	r := self.Report
+	if self.Before != nil {
+		if self.Comparison != nil && self.Comparison.Mode == diff.ModeVariants {
+			return fmt.Sprintf("%s %s ↔ %s", r.Build.AppName, self.Before.Build.BuildVariant, r.Build.BuildVariant)
+		}
+		return fmt.Sprintf("%s %s (%s) → %s (%s)",
+			r.Build.AppName,
+			self.Before.Build.VersionName, self.Before.Build.VersionCode,
+			r.Build.VersionName, r.Build.VersionCode,
+		)
+	}
+	return fmt.Sprintf("%s %s (%s) %s", r.Build.AppName, r.Build.VersionName, r.Build.VersionCode, r.Build.BuildVariant)
+}
+
+func (self *Model) status(width int, count int) string {
+	if self.isSearching {
+		return truncate("/"+self.query+"▏", width)
+	}
+
+	parts := []string{fmt.Sprintf("%d/%d", min(self.cursor[self.CurrentTab()]+1, count), count)}
+	if self.query != "" {
+		parts = append(parts, fmt.Sprintf("search: %q (esc to clear)", self.query))
+	}
+	if filter := self.FilterName(); filter != "" {
+		parts = append(parts, "f filter: "+filter)
+	}
+	if self.CurrentTab() == TabTree {
+		parts = append(parts, "enter expand")
+	}
+	parts = append(parts, "tab switch", "/ search", "q quit")
+	return helpColor.Sprint(truncate(strings.Join(parts, "  "), width))
+}
+
+func styleColor(style rowStyle) *color.Color {
+	switch style {
+	case styleHeader:
+		return headerColor
+	case styleAdded:
+		return addedColor
+	case styleRemoved:
+		return removedColor
+	case styleUpgraded:
+		return upgradedColor
+	case styleDowngraded:
+		return downgradedColor
+	case styleChanged:
+		return changedColor
+	case styleWarning:
+		return warningColor
+	}
+	return color.New(color.Reset)
+}
+
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max(0, width-1)]) + "…"
+}
+
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,84 @@
+package verification
+
+import (
+	"encoding/xml"
+	"lampa/internal/report"
+)
+
+// Gradle dependency verification file (`gradle/verification-metadata.xml`).
+// See https://docs.gradle.org/current/userguide/dependency_verification.html
+
+type Metadata struct {
+	XMLName           xml.Name    `xml:"verification-metadata"`
+	Xmlns             string      `xml:"xmlns,attr"`
+	XmlnsXsi          string      `xml:"xmlns:xsi,attr"`
+	XsiSchemaLocation string      `xml:"xsi:schemaLocation,attr"`
+	Configuration     Config      `xml:"configuration"`
+	Components        []Component `xml:"components>component"`
+}
+
+type Config struct {
+	VerifyMetadata   bool `xml:"verify-metadata"`
+	VerifySignatures bool `xml:"verify-signatures"`
+}
+
+type Component struct {
+	Group     string     `xml:"group,attr"`
+	Name      string     `xml:"name,attr"`
+	Version   string     `xml:"version,attr"`
+	Artifacts []Artifact `xml:"artifact"`
+}
+
+type Artifact struct {
+	Name   string   `xml:"name,attr"`
+	Sha256 Checksum `xml:"sha256"`
+}
+
+type Checksum struct {
+	Value  string `xml:"value,attr"`
+	Origin string `xml:"origin,attr,omitempty"`
+}
+
+const origin = "Generated by Lampa"
+
+// FromReport builds verification metadata from artifact checksums recorded in the report.
+func FromReport(r *report.Report) Metadata {
+	result := Metadata{
+		Xmlns:             "https://schema.gradle.org/dependency-verification",
+		XmlnsXsi:          "http://www.w3.org/2001/XMLSchema-instance",
+		XsiSchemaLocation: "https://schema.gradle.org/dependency-verification https://schema.gradle.org/dependency-verification/dependency-verification-1.3.xsd",
+		Configuration: Config{
+			VerifyMetadata:   true,
+			VerifySignatures: false,
+		},
+	}
+
+	for _, d := range r.Build.Dependencies.Compile {
+		if len(d.Files) == 0 {
+			continue
+		}
+
+		component := Component{
+			Group:   d.Group,
+			Name:    d.Name,
+			Version: d.Version,
+		}
+		for _, f := range d.Files {
+			component.Artifacts = append(component.Artifacts, Artifact{
+				Name:   f.Name,
+				Sha256: Checksum{Value: f.Sha256, Origin: origin},
+			})
+		}
+		result.Components = append(result.Components, component)
+	}
+
+	return result
+}
+
+func (self Metadata) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(self, "", "   ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
@@ -0,0 +1,38 @@
+package verification
+
+import (
+	"lampa/internal/report"
+	"strings"
+	"testing"
+)
+
+func TestFromReport(t *testing.T) {
+	r := &report.Report{}
+	r.Build.Dependencies.Compile = []report.CoordinatedDependency{
+		{Group: "com.example", Name: "lib", Version: "1.0.0", Files: []report.ArtifactFile{
+			{Name: "lib-1.0.0.jar", Sha256: "abc"},
+		}},
+		{Group: "com.example", Name: "no-files", Version: "1.0.0"},
+	}
+
+	metadata := FromReport(r)
+	if len(metadata.Components) != 1 {
+		t.Fatalf("Expected 1 component, got: %#v", metadata.Components)
+	}
+
+	data, err := metadata.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	xml := string(data)
+	for _, expected := range []string{
+		`<component group="com.example" name="lib" version="1.0.0">`,
+		`<artifact name="lib-1.0.0.jar">`,
+		`<sha256 value="abc" origin="Generated by Lampa">`,
+		`<verify-metadata>true</verify-metadata>`,
+	} {
+		if !strings.Contains(xml, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, xml)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package versions
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Implementation of Maven's ComparableVersion ordering.
+// See https://maven.apache.org/ref/current/maven-artifact/apidocs/org/apache/maven/artifact/versioning/ComparableVersion.html
+
+// Compare returns -1 if v1 < v2, 0 if they are equal and 1 if v1 > v2.
+func Compare(v1 string, v2 string) int {
+	return parse(v1).compareTo(parse(v2))
+}
+
+func IsLater(v1 string, v2 string) bool {
+	return Compare(v1, v2) > 0
+}
+
+// IsComparable checks if ordering between versions makes sense
+// (e.g. commit hashes or versions in different schemes are not comparable).
+func IsComparable(v1 string, v2 string) bool {
+	if IsCommitHash(v1) || IsCommitHash(v2) {
+		return false
+	}
+	return startsWithDigit(v1) == startsWithDigit(v2)
+}
+
+var commitHashRegex = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+func IsCommitHash(v string) bool {
+	v = strings.ToLower(v)
+	return commitHashRegex.MatchString(v) && strings.ContainsAny(v, "abcdef")
+}
+
+func startsWithDigit(v string) bool {
+	return len(v) > 0 && isDigit(rune(v[0]))
+}
+
+type itemKind int
+
+const (
+	kindInt itemKind = iota
+	kindString
+	kindList
+)
+
+type item struct {
+	kind itemKind
+
+	// Integer value without leading zeros (may exceed int64)
+	number string
+	// Qualifier value
+	value string
+	list  []*item
+}
+
+var qualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var aliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+const releaseQualifierIndex = "5"
+
+func parse(version string) *item {
+	version = strings.ToLower(version)
+
+	root := &item{kind: kindList}
+	list := root
+	stack := []*item{root}
+
+	isDigitSeq := false
+	start := 0
+	runes := []rune(version)
+
+	for i, c := range runes {
+		switch {
+		case c == '.':
+			if i == start {
+				list.add(intItem("0"))
+			} else {
+				list.add(parseItem(isDigitSeq, string(runes[start:i])))
+			}
+			start = i + 1
+		case c == '-':
+			if i == start {
+				list.add(intItem("0"))
+			} else {
+				list.add(parseItem(isDigitSeq, string(runes[start:i])))
+			}
+			start = i + 1
+
+			sub := &item{kind: kindList}
+			list.add(sub)
+			list = sub
+			stack = append(stack, sub)
+		case isDigit(c):
+			if !isDigitSeq && i > start {
+				list.add(stringItem(string(runes[start:i]), true))
+				start = i
+
+				sub := &item{kind: kindList}
+				list.add(sub)
+				list = sub
+				stack = append(stack, sub)
+			}
+			isDigitSeq = true
+		default:
+			if isDigitSeq && i > start {
+				list.add(parseItem(true, string(runes[start:i])))
+				start = i
+
+				sub := &item{kind: kindList}
+				list.add(sub)
+				list = sub
+				stack = append(stack, sub)
+			}
+			isDigitSeq = false
+		}
+	}
+
+	if len(runes) > start {
+		list.add(parseItem(isDigitSeq, string(runes[start:])))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+
+	return root
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+func parseItem(isDigit bool, s string) *item {
+	if isDigit {
+		return intItem(s)
+	}
+	return stringItem(s, false)
+}
+
+func intItem(s string) *item {
+	s = strings.TrimLeft(s, "0")
+	if s == "" {
+		s = "0"
+	}
+	return &item{kind: kindInt, number: s}
+}
+
+func stringItem(s string, followedByDigit bool) *item {
+	if followedByDigit && len(s) == 1 {
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := aliases[s]; ok {
+		s = alias
+	}
+	return &item{kind: kindString, value: s}
+}
+
+func (self *item) add(it *item) {
+	self.list = append(self.list, it)
+}
+
+func (self *item) isNull() bool {
+	switch self.kind {
+	case kindInt:
+		return self.number == "0"
+	case kindString:
+		return comparableQualifier(self.value) == releaseQualifierIndex
+	default:
+		return len(self.list) == 0
+	}
+}
+
+// normalize removes trailing "null" items (e.g. "1.0.0" -> "1").
+func (self *item) normalize() {
+	for i := len(self.list) - 1; i >= 0; i-- {
+		last := self.list[i]
+		if last.isNull() {
+			self.list = append(self.list[:i], self.list[i+1:]...)
+		} else if last.kind != kindList {
+			break
+		}
+	}
+}
+
+func comparableQualifier(q string) string {
+	for i, it := range qualifiers {
+		if it == q {
+			return string(rune('0' + i))
+		}
+	}
+	// Unknown qualifiers are considered after known ones (in lexical order)
+	return string(rune('0'+len(qualifiers))) + "-" + q
+}
+
+// compareTo compares item with other one (nil is treated as an absent item).
+func (self *item) compareTo(other *item) int {
+	switch self.kind {
+	case kindInt:
+		if other == nil {
+			if self.number == "0" {
+				return 0
+			}
+			return 1
+		}
+		switch other.kind {
+		case kindInt:
+			return compareNumbers(self.number, other.number)
+		default:
+			// 1.1 > 1-sp, 1.1 > 1-1
+			return 1
+		}
+
+	case kindString:
+		if other == nil {
+			return strings.Compare(comparableQualifier(self.value), releaseQualifierIndex)
+		}
+		switch other.kind {
+		case kindString:
+			return strings.Compare(comparableQualifier(self.value), comparableQualifier(other.value))
+		default:
+			return -1
+		}
+
+	default:
+		if other == nil {
+			if len(self.list) == 0 {
+				return 0
+			}
+			return self.list[0].compareTo(nil)
+		}
+		switch other.kind {
+		case kindInt:
+			return -1
+		case kindString:
+			return 1
+		}
+
+		for i := 0; i < max(len(self.list), len(other.list)); i++ {
+			var l, r *item
+			if i < len(self.list) {
+				l = self.list[i]
+			}
+			if i < len(other.list) {
+				r = other.list[i]
+			}
+
+			result := 0
+			if l == nil {
+				if r != nil {
+					result = -r.compareTo(nil)
+				}
+			} else {
+				result = l.compareTo(r)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+}
+
+func compareNumbers(n1 string, n2 string) int {
+	if len(n1) != len(n2) {
+		if len(n1) < len(n2) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(n1, n2)
+}
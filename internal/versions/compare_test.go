@@ -0,0 +1,69 @@
+package versions
+
+import "testing"
+
+func TestCompare_Ordering(t *testing.T) {
+	// Each version is lower than the next one
+	ordered := [][]string{
+		{"1", "1.1", "1.2", "1.10", "2"},
+		{"1.0.0-alpha01", "1.0.0-alpha05", "1.0.0-alpha10", "1.0.0-beta01", "1.0.0-rc01", "1.0.0-SNAPSHOT", "1.0.0", "1.0.0-sp1", "1.0.1"},
+		{"2.0.0-M1", "2.0.0-RC1", "2.0.0-RC2", "2.0.0"},
+		{"1.0a1", "1.0b1", "1.0"},
+		{"r07", "r08", "r09"},
+		{"33.1.0-android", "33.2.0-android", "33.2.1-jre"},
+		{"1.0.0.1", "1.0.0.2", "1.0.1.0"},
+		{"1.9.22", "1.9.23", "2.0.0-Beta1", "2.0.0"},
+		{"9999999999999999999", "10000000000000000000"},
+	}
+
+	for _, versions := range ordered {
+		for i := 0; i < len(versions)-1; i++ {
+			v1 := versions[i]
+			v2 := versions[i+1]
+			if Compare(v1, v2) >= 0 {
+				t.Errorf("Expected %s < %s", v1, v2)
+			}
+			if Compare(v2, v1) <= 0 {
+				t.Errorf("Expected %s > %s", v2, v1)
+			}
+		}
+	}
+}
+
+func TestCompare_Equality(t *testing.T) {
+	equal := [][2]string{
+		{"1", "1.0.0"},
+		{"1.0", "1.0-ga"},
+		{"1.0", "1.0.final"},
+		{"1.0-RC1", "1.0-cr1"},
+		{"1.0-alpha1", "1.0-a1"},
+		{"1.0.0-ALPHA01", "1.0.0-alpha1"},
+	}
+
+	for _, pair := range equal {
+		if Compare(pair[0], pair[1]) != 0 {
+			t.Errorf("Expected %s == %s", pair[0], pair[1])
+		}
+	}
+}
+
+func TestIsComparable(t *testing.T) {
+	cases := []struct {
+		v1         string
+		v2         string
+		comparable bool
+	}{
+		{"1.0.0", "1.0.1", true},
+		{"r08", "r09", true},
+		{"1.0.0", "3a6b2fe", false},
+		{"3a6b2fe", "9c1d2ab", false},
+		{"1.0.0", "master-SNAPSHOT", false},
+		{"1234567", "1234568", true},
+	}
+
+	for _, c := range cases {
+		if IsComparable(c.v1, c.v2) != c.comparable {
+			t.Errorf("IsComparable(%q, %q) expected to be %v", c.v1, c.v2, c.comparable)
+		}
+	}
+}
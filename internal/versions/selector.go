@@ -0,0 +1,89 @@
+package versions
+
+import (
+	"strings"
+)
+
+// Matches checks if version `v` satisfies the requested version (selector or exact version).
+// Resolution of "latest.*" selectors depends on repository metadata, so any version matches them.
+func Matches(selector string, v string) bool {
+	s := strings.TrimSpace(selector)
+	switch {
+	case strings.HasPrefix(s, "latest."):
+		return true
+	case strings.HasSuffix(s, "+"):
+		return strings.HasPrefix(v, strings.TrimSuffix(s, "+"))
+	case isRange(s):
+		ranges, ok := parseRanges(s)
+		if !ok {
+			// Can't tell, so it is not reported as mismatch
+			return true
+		}
+		for _, r := range ranges {
+			if r.contains(v) {
+				return true
+			}
+		}
+		return false
+	}
+	return s == v
+}
+
+func isRange(s string) bool {
+	return strings.HasPrefix(s, "[") || strings.HasPrefix(s, "(") || strings.HasPrefix(s, "]")
+}
+
+// versionRange is a Maven/Gradle version range ("[1.0,2.0)", "]1.0,2.0[", "[1.0]").
+type versionRange struct {
+	lower, upper                   string
+	lowerInclusive, upperInclusive bool
+}
+
+func (self versionRange) contains(v string) bool {
+	if self.lower != "" {
+		c := Compare(v, self.lower)
+		if c < 0 || (c == 0 && !self.lowerInclusive) {
+			return false
+		}
+	}
+	if self.upper != "" {
+		c := Compare(v, self.upper)
+		if c > 0 || (c == 0 && !self.upperInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseRanges parses union of ranges ("[1.0,2.0),[3.0,)").
+func parseRanges(s string) ([]versionRange, bool) {
+	result := []versionRange{}
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		open := rest[0]
+		if open != '[' && open != '(' && open != ']' {
+			return nil, false
+		}
+		end := strings.IndexAny(rest[1:], "])[")
+		if end == -1 {
+			return nil, false
+		}
+		end++
+		closing := rest[end]
+		body := rest[1:end]
+		rest = strings.TrimLeft(rest[end+1:], ", ")
+
+		lower, upper, isBounded := strings.Cut(body, ",")
+		if !isBounded {
+			// Exact version ("[1.0]")
+			upper = lower
+		}
+		result = append(result, versionRange{
+			lower:          strings.TrimSpace(lower),
+			upper:          strings.TrimSpace(upper),
+			lowerInclusive: open == '[',
+			upperInclusive: closing == ']',
+		})
+	}
+	return result, len(result) > 0
+}
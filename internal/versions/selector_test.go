@@ -0,0 +1,35 @@
+package versions
+
+import "testing"
+
+func TestMatches(t *testing.T) {
+	cases := []struct {
+		selector string
+		version  string
+		expected bool
+	}{
+		{"1.+", "1.5.0", true},
+		{"1.+", "2.0.0", false},
+		{"+", "3.0.0", true},
+		{"latest.release", "4.12.0", true},
+		{"[1.0,2.0)", "1.9.9", true},
+		{"[1.0,2.0)", "1.0", true},
+		{"[1.0,2.0)", "2.0", false},
+		{"]1.0,2.0[", "1.0", false},
+		{"(,1.0]", "1.0", true},
+		{"(,1.0]", "1.1", false},
+		{"[1.0,)", "5.0", true},
+		{"[1.5]", "1.5", true},
+		{"[1.5]", "1.6", false},
+		{"[1.0,1.2],[1.5,)", "1.3", false},
+		{"[1.0,1.2],[1.5,)", "1.6", true},
+		{"1.0.0", "1.0.0", true},
+		{"1.0.0", "1.0.1", false},
+	}
+
+	for _, c := range cases {
+		if actual := Matches(c.selector, c.version); actual != c.expected {
+			t.Errorf("Matches(%q, %q) expected to be %v, got %v", c.selector, c.version, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package versions
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Stability string
+
+const (
+	Stable   Stability = "stable"
+	Alpha    Stability = "alpha"
+	Beta     Stability = "beta"
+	RC       Stability = "rc"
+	Snapshot Stability = "snapshot"
+	Dev      Stability = "dev"
+	GitHash  Stability = "git-hash"
+)
+
+// Stabilities in display order.
+var Stabilities = []Stability{Stable, RC, Beta, Alpha, Dev, Snapshot, GitHash}
+
+func (self Stability) IsStable() bool {
+	return self == Stable
+}
+
+// Label returns human-readable name.
+func (self Stability) Label() string {
+	switch self {
+	case RC:
+		return "RC"
+	case Snapshot:
+		return "SNAPSHOT"
+	case GitHash:
+		return "Git hash"
+	default:
+		return strings.ToUpper(string(self[:1])) + string(self[1:])
+	}
+}
+
+var qualifierStabilities = map[string]Stability{
+	"alpha":        Alpha,
+	"beta":         Beta,
+	"milestone":    Beta,
+	"rc":           RC,
+	"cr":           RC,
+	"snapshot":     Snapshot,
+	"dev":          Dev,
+	"eap":          Dev,
+	"preview":      Dev,
+	"pre":          Dev,
+	"nightly":      Dev,
+	"canary":       Dev,
+	"experimental": Dev,
+}
+
+// Classify detects stability of the version by its qualifiers.
+// The least stable qualifier wins (e.g. "1.0-rc1-SNAPSHOT" is a snapshot).
+func Classify(v string) Stability {
+	if IsCommitHash(v) {
+		return GitHash
+	}
+
+	result := Stable
+	for _, token := range tokenize(strings.ToLower(v)) {
+		stability, ok := qualifierStabilities[token.value]
+		if !ok {
+			// Short forms are allowed only when followed by number (e.g. "1.0a1", "2.0.0-b3", "3.0.0-M2")
+			if token.followedByDigit {
+				switch token.value {
+				case "a":
+					stability, ok = Alpha, true
+				case "b", "m":
+					stability, ok = Beta, true
+				}
+			}
+		}
+		if ok && rank(stability) > rank(result) {
+			result = stability
+		}
+	}
+	return result
+}
+
+func rank(s Stability) int {
+	switch s {
+	case Stable:
+		return 0
+	case RC:
+		return 1
+	case Beta:
+		return 2
+	case Alpha:
+		return 3
+	case Dev:
+		return 4
+	default:
+		return 5
+	}
+}
+
+type token struct {
+	value           string
+	followedByDigit bool
+}
+
+// tokenize splits version into alphabetic tokens.
+func tokenize(v string) []token {
+	result := []token{}
+	runes := []rune(v)
+
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		isLetter := i < len(runes) && unicode.IsLetter(runes[i])
+		if isLetter && start == -1 {
+			start = i
+		} else if !isLetter && start != -1 {
+			result = append(result, token{
+				value:           string(runes[start:i]),
+				followedByDigit: i < len(runes) && unicode.IsDigit(runes[i]),
+			})
+			start = -1
+		}
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package versions
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	cases := map[string]Stability{
+		"1.0.0":                Stable,
+		"33.1.0-android":       Stable,
+		"2.1.0.Final":          Stable,
+		"r08":                  Stable,
+		"1.4.0-alpha08":        Alpha,
+		"1.0a1":                Alpha,
+		"1.1.0-beta02":         Beta,
+		"2.0.0-Beta1":          Beta,
+		"3.0.0-M2":             Beta,
+		"1.0-milestone-1":      Beta,
+		"1.0.0-m":              Stable,
+		"2024.01.15-m-build":   Stable,
+		"1.1.0-rc01":           RC,
+		"2.0.0-RC1":            RC,
+		"1.0.0-SNAPSHOT":       Snapshot,
+		"1.0.0-rc1-SNAPSHOT":   Snapshot,
+		"1.9.20-dev-1234":      Dev,
+		"2.0.0-eap-42":         Dev,
+		"3a6b2fe":              GitHash,
+		"1.0.0-android-beta01": Beta,
+	}
+
+	for v, expected := range cases {
+		if actual := Classify(v); actual != expected {
+			t.Errorf("Classify(%q) expected to be %s, got %s", v, expected, actual)
+		}
+	}
+}